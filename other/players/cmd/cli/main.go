@@ -6,11 +6,24 @@ import (
 	"os"
 
 	"github.com/mgxian/tdd-practice/other/players"
+	"github.com/mgxian/tdd-practice/task2/args2"
 )
 
-const dbFilename = "game.db.json"
+const schema = "d:string:game.db.json"
 
 func main() {
+	config, err := args2.NewParser(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.ParseArgs(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	dbFilename, err := config.StringValueOf("d")
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	store, close, err := players.FileSystemPlayerStoreFromFile(dbFilename)
 	defer close()
 	if err != nil {
@@ -1,15 +1,34 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/mgxian/tdd-practice/other/players"
+	"github.com/mgxian/tdd-practice/task2/args2"
 )
 
-const dbFilename = "game.db.json"
+const schema = "p:int:5000 d:string:game.db.json"
 
 func main() {
+	config, err := args2.NewParser(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.ParseArgs(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	port, err := config.IntValueOf("p")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dbFilename, err := config.StringValueOf("d")
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	store, close, err := players.FileSystemPlayerStoreFromFile(dbFilename)
 	defer close()
 	if err != nil {
@@ -18,7 +37,8 @@ func main() {
 
 	server := players.NewPlayerServer(store)
 
-	if err := http.ListenAndServe(":5000", server); err != nil {
-		log.Fatalf("could not listen on port 5000 %v", err)
+	addr := fmt.Sprintf(":%d", port)
+	if err := http.ListenAndServe(addr, server); err != nil {
+		log.Fatalf("could not listen on port %d %v", port, err)
 	}
 }
@@ -0,0 +1,165 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidNumber is returned when a decimal string is not an integer.
+var ErrInvalidNumber = errors.New("invalid decimal number")
+
+// ErrUnsupportedPredicate is returned when a predicate can't evaluate a
+// number, e.g. a Func predicate given a number beyond int.
+var ErrUnsupportedPredicate = errors.New("predicate doesn't support the number")
+
+// DecimalPredicate is a Predicate that can evaluate numbers of any size
+// given as decimal strings.
+type DecimalPredicate interface {
+	Predicate
+	MatchDecimal(number string) bool
+}
+
+func isDecimal(number string) bool {
+	digits := strings.TrimPrefix(number, "-")
+	if digits == "" || (len(digits) > 1 && digits[0] == '0') || number == "-0" {
+		return false
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDecimalDivisibleBy(number string, divisor int) bool {
+	if divisor == 0 {
+		return false
+	}
+	if divisor > math.MaxInt64/10 || divisor < -math.MaxInt64/10 {
+		// remainder*10 would overflow, fall back to big.Int
+		n, _ := new(big.Int).SetString(number, 10)
+		return new(big.Int).Rem(n, big.NewInt(int64(divisor))).Sign() == 0
+	}
+	if divisor < 0 {
+		divisor = -divisor
+	}
+	remainder := 0
+	for i := 0; i < len(number); i++ {
+		if number[i] == '-' {
+			continue
+		}
+		remainder = (remainder*10 + int(number[i]-'0')) % divisor
+	}
+	return remainder == 0
+}
+
+func (d divisibleBy) MatchDecimal(number string) bool {
+	return isDecimalDivisibleBy(number, d.divisor)
+}
+
+// inBase converts a decimal string to base, it is a no-op for base 10.
+func inBase(number string, base int) string {
+	if base == 10 {
+		return number
+	}
+	n, _ := new(big.Int).SetString(number, 10)
+	return n.Text(base)
+}
+
+func (c contains) MatchDecimal(number string) bool {
+	return isValidBase(c.base) && strings.Contains(inBase(number, c.base), strconv.FormatInt(int64(c.subNumber), c.base))
+}
+
+func (e endsWith) MatchDecimal(number string) bool {
+	return isValidBase(e.base) && strings.HasSuffix(inBase(number, e.base), strconv.FormatInt(int64(e.suffix), e.base))
+}
+
+func (d digitSum) MatchDecimal(number string) bool {
+	total := 0
+	for i := 0; i < len(number); i++ {
+		if number[i] != '-' {
+			total += int(number[i] - '0')
+		}
+	}
+	return total == d.sum
+}
+
+func matchDecimal(p Predicate, number string) (bool, error) {
+	switch p := p.(type) {
+	case anyOf:
+		for _, sub := range p {
+			ok, err := matchDecimal(sub, number)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case all:
+		for _, sub := range p {
+			ok, err := matchDecimal(sub, number)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case DecimalPredicate:
+		return p.MatchDecimal(number), nil
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return false, ErrUnsupportedPredicate
+	}
+	return p.Match(n), nil
+}
+
+// PlayDecimal is Play for a number of any size given as a decimal string.
+func (g *Game) PlayDecimal(number string) (string, error) {
+	if !isDecimal(number) {
+		return "", ErrInvalidNumber
+	}
+
+	var matched []Rule
+	for _, r := range g.rules {
+		ok, err := matchDecimal(r.Predicate, number)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			matched = append(matched, r)
+		}
+	}
+
+	used := g.policy.Combine(matched)
+	if len(used) == 0 {
+		return g.renderDecimal(number), nil
+	}
+	return g.joinWords(used), nil
+}
+
+// renderDecimal renders a decimal string, renderers other than Base only
+// get numbers that fit in int.
+func (g *Game) renderDecimal(number string) string {
+	if b, ok := g.renderer.(Base); ok {
+		return inBase(number, b.radix())
+	}
+	if n, err := strconv.Atoi(number); err == nil {
+		return g.render(n)
+	}
+	return number
+}
+
+// PlayBig is Play for a number of any size.
+func (g *Game) PlayBig(number *big.Int) (string, error) {
+	return g.PlayDecimal(number.String())
+}
+
+// FizzBuzzBig is FizzBuzz for a number of any size.
+func FizzBuzzBig(number *big.Int) string {
+	word, _ := defaultGame.PlayBig(number)
+	return word
+}
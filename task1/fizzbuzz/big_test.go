@@ -0,0 +1,96 @@
+package fizzbuzz
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPlayDecimal(t *testing.T) {
+	t.Run("agrees with fizzbuzz", func(t *testing.T) {
+		for number := -200; number <= 1000; number++ {
+			got := FizzBuzzBig(big.NewInt(int64(number)))
+			want := FizzBuzz(number)
+			if got != want {
+				t.Errorf("%d got %s, want %s", number, got, want)
+			}
+		}
+	})
+
+	t.Run("thousands of digits", func(t *testing.T) {
+		huge := new(big.Int).Exp(big.NewInt(10), big.NewInt(3000), nil)
+		hugeTests := []struct {
+			number *big.Int
+			result string
+		}{
+			{new(big.Int).Add(huge, big.NewInt(1)), "1" + strings.Repeat("0", 2999) + "1"},
+			{new(big.Int).Add(huge, big.NewInt(2)), "fizz"},
+			{huge, "buzz"},
+			{new(big.Int).Add(huge, big.NewInt(10)), "buzz"},
+			{new(big.Int).Add(huge, big.NewInt(35)), "fizzbuzz"},
+			{new(big.Int).Mul(huge, big.NewInt(15)), "fizzbuzz"},
+			{new(big.Int).Add(huge, big.NewInt(31)), "fizz"},
+		}
+
+		for _, tt := range hugeTests {
+			got := FizzBuzzBig(tt.number)
+			if got != tt.result {
+				t.Errorf("got %.20s, want %.20s", got, tt.result)
+			}
+		}
+	})
+
+	t.Run("zero divisor never matches", func(t *testing.T) {
+		game := NewGame().AddRule(DivisibleBy(0), "zero")
+		for _, number := range []string{"0", "5", "123456789012345678901234567890"} {
+			got, err := game.PlayDecimal(number)
+			if err != nil {
+				t.Fatalf("%s got error %v but didn't want one", number, err)
+			}
+			if got != number {
+				t.Errorf("%s got %s, want %s", number, got, number)
+			}
+		}
+	})
+
+	t.Run("large divisors agree with play", func(t *testing.T) {
+		divisors := []int{math.MaxInt64, math.MaxInt64 / 10, math.MaxInt64/10 + 1, math.MinInt64, -math.MaxInt64}
+		numbers := []int{0, 1, math.MaxInt64, math.MaxInt64 - 1, math.MinInt64, math.MinInt64 + 1, math.MaxInt64 / 10 * 7}
+		for _, divisor := range divisors {
+			game := NewGame().AddRule(DivisibleBy(divisor), "big")
+			for _, number := range numbers {
+				got, err := game.PlayDecimal(strconv.Itoa(number))
+				if err != nil {
+					t.Fatalf("%d got error %v but didn't want one", number, err)
+				}
+				if want := game.Play(number); got != want {
+					t.Errorf("%d by %d got %s, want %s", number, divisor, got, want)
+				}
+			}
+		}
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		for _, number := range []string{"", "-", "12a", "007", "-0", "+1"} {
+			_, err := defaultGame.PlayDecimal(number)
+			if err != ErrInvalidNumber {
+				t.Errorf("%q got %v, want %v", number, err, ErrInvalidNumber)
+			}
+		}
+	})
+
+	t.Run("func predicate", func(t *testing.T) {
+		game := NewGame().AddRule(Func("even", func(n int) bool { return n%2 == 0 }), "even")
+		got, err := game.PlayDecimal(strconv.Itoa(42))
+		if err != nil || got != "even" {
+			t.Errorf("got %s %v, want even", got, err)
+		}
+
+		_, err = game.PlayDecimal("1" + strings.Repeat("0", 30))
+		if err != ErrUnsupportedPredicate {
+			t.Errorf("got %v, want %v", err, ErrUnsupportedPredicate)
+		}
+	})
+}
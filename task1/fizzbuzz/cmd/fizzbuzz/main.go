@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/mgxian/tdd-practice/task1/fizzbuzz"
+)
+
+func main() {
+	from := flag.Int("from", 1, "first number of the range")
+	to := flag.Int("to", 100, "last number of the range")
+	step := flag.Int("step", 1, "distance between two numbers")
+	ruleSet := flag.String("rules", "classic", "rule set: classic, fizzbuzz or fizzbuzzwhizz")
+	ruleFile := flag.String("rulefile", "", "rule file describing the game, replaces the rule set")
+	policy := flag.String("policy", "", "combination policy overriding the rule set's one")
+	format := flag.String("format", "text", "output format: text, csv or json")
+	vocabulary := flag.String("vocabulary", "", "file translating the words, text or json")
+	numbers := flag.String("numbers", "", "number renderer: binary, octal, decimal, hex, roman or english, decimal by default")
+	workers := flag.Int("workers", 0, "evaluate chunks of the range on that many workers, 0 evaluates in order")
+	explain := flag.Bool("explain", false, "explain every result, text or json format only")
+	flag.Parse()
+
+	game, err := fizzbuzz.RuleSetByName(*ruleSet)
+	if err != nil {
+		log.Fatalf("%s: %v", *ruleSet, err)
+	}
+	if *ruleFile != "" {
+		if game, err = fizzbuzz.LoadRulesFile(*ruleFile); err != nil {
+			log.Fatalf("%s: %v", *ruleFile, err)
+		}
+	}
+
+	if *policy != "" {
+		p, err := fizzbuzz.PolicyByName(*policy)
+		if err != nil {
+			log.Fatalf("%s: %v", *policy, err)
+		}
+		game.SetPolicy(p)
+	}
+
+	if *vocabulary != "" {
+		v, err := fizzbuzz.LoadVocabularyFile(*vocabulary)
+		if err != nil {
+			log.Fatal(err)
+		}
+		game.SetVocabulary(v)
+	}
+
+	if *numbers != "" {
+		renderer, err := fizzbuzz.RendererByName(*numbers)
+		if err != nil {
+			log.Fatalf("%s: %v", *numbers, err)
+		}
+		game.SetRenderer(renderer)
+	}
+
+	write := game.WriteRange
+	switch {
+	case *explain && *workers > 0:
+		log.Fatal("--explain can't be used with --workers")
+	case *explain:
+		write = game.WriteExplanations
+	case *workers > 0:
+		write = fizzbuzz.NewGenerator(game, *workers, 0).WriteRange
+	}
+	if err := write(os.Stdout, *from, *to, *step, fizzbuzz.Format(*format)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mgxian/tdd-practice/other/players"
+	"github.com/mgxian/tdd-practice/task1/fizzbuzz"
+)
+
+func main() {
+	ruleSet := flag.String("rules", "classic", "rule set: classic, fizzbuzz or fizzbuzzwhizz")
+	dbFilename := flag.String("db", "", "player database recording the winners, none when empty")
+	flag.Parse()
+
+	game, err := fizzbuzz.RuleSetByName(*ruleSet)
+	if err != nil {
+		log.Fatalf("%s: %v", *ruleSet, err)
+	}
+
+	var store players.PlayerStore
+	if *dbFilename != "" {
+		fileStore, close, err := players.FileSystemPlayerStoreFromFile(*dbFilename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer close()
+		store = fileStore
+	}
+
+	fmt.Println("Let's play fizzbuzz")
+	fmt.Println("Answer your number, a wrong answer puts you out")
+	fizzbuzz.NewPartyCLI(game, store, os.Stdin, os.Stdout).PlayParty()
+}
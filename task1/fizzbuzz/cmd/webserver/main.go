@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/mgxian/tdd-practice/task1/fizzbuzz"
+)
+
+func main() {
+	addr := flag.String("addr", ":5001", "address to listen on")
+	ruleSet := flag.String("rules", "classic", "rule set: classic, fizzbuzz or fizzbuzzwhizz")
+	flag.Parse()
+
+	game, err := fizzbuzz.RuleSetByName(*ruleSet)
+	if err != nil {
+		log.Fatalf("%s: %v", *ruleSet, err)
+	}
+
+	server := fizzbuzz.NewServer(game)
+
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatalf("could not listen on %s %v", *addr, err)
+	}
+}
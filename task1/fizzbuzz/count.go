@@ -0,0 +1,446 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRange is returned when a range to count is empty or negative.
+var ErrInvalidRange = errors.New("invalid range, want 0 <= from <= to")
+
+// ErrTooComplex is returned when a game has too many distinct conditions
+// or too large moduli to be counted.
+var ErrTooComplex = errors.New("rules too complex to count")
+
+const (
+	maxCountAtoms   = 16
+	maxCountModulus = 1 << 20
+)
+
+// Counts is how often every output of a game occurs in a range, Numbers
+// counts the numbers no rule was used for.
+type Counts struct {
+	Words   map[string]int64
+	Numbers int64
+}
+
+// Total is the number of numbers counted.
+func (c Counts) Total() int64 {
+	total := c.Numbers
+	for _, n := range c.Words {
+		total += n
+	}
+	return total
+}
+
+// Count counts the outputs of the numbers in [from, to] without evaluating
+// them one by one. Divisibility is counted by inclusion–exclusion and
+// containment by digit dynamic programming, Func predicates aren't
+// supported. [0, MaxInt64] holds more numbers than an int64 counts and is
+// an invalid range.
+func (g *Game) Count(from, to int) (Counts, error) {
+	if from < 0 || from > to || (from == 0 && to == math.MaxInt64) {
+		return Counts{}, ErrInvalidRange
+	}
+
+	atoms, err := collectAtoms(g.rules)
+	if err != nil {
+		return Counts{}, err
+	}
+
+	exact := make([]int64, 1<<uint(len(atoms)))
+	for mask := range exact {
+		set := atomsOf(atoms, mask)
+		upper, err := countUpTo(set, to)
+		if err != nil {
+			return Counts{}, err
+		}
+		lower, err := countUpTo(set, from-1)
+		if err != nil {
+			return Counts{}, err
+		}
+		exact[mask] = upper - lower
+	}
+
+	for i := range atoms {
+		bit := 1 << uint(i)
+		for mask := range exact {
+			if mask&bit == 0 {
+				exact[mask] -= exact[mask|bit]
+			}
+		}
+	}
+
+	counts := Counts{Words: make(map[string]int64)}
+	for mask, n := range exact {
+		if n == 0 {
+			continue
+		}
+		var matched []Rule
+		for _, r := range g.rules {
+			if matchAtoms(r.Predicate, atoms, mask) {
+				matched = append(matched, r)
+			}
+		}
+		used := g.policy.Combine(matched)
+		if len(used) == 0 {
+			counts.Numbers += n
+		} else {
+			counts.Words[g.joinWords(used)] += n
+		}
+	}
+	return counts, nil
+}
+
+func collectAtoms(rules []Rule) ([]Predicate, error) {
+	var atoms []Predicate
+	var collect func(p Predicate) error
+	collect = func(p Predicate) error {
+		switch p := p.(type) {
+		case all:
+			for _, sub := range p {
+				if err := collect(sub); err != nil {
+					return err
+				}
+			}
+			return nil
+		case anyOf:
+			for _, sub := range p {
+				if err := collect(sub); err != nil {
+					return err
+				}
+			}
+			return nil
+		case divisibleBy, contains, endsWith, digitSum:
+			for _, a := range atoms {
+				if a == p {
+					return nil
+				}
+			}
+			atoms = append(atoms, p)
+			return nil
+		default:
+			return ErrUnsupportedPredicate
+		}
+	}
+
+	for _, r := range rules {
+		if err := collect(r.Predicate); err != nil {
+			return nil, err
+		}
+	}
+	if len(atoms) > maxCountAtoms {
+		return nil, ErrTooComplex
+	}
+	return atoms, nil
+}
+
+func atomsOf(atoms []Predicate, mask int) []Predicate {
+	var result []Predicate
+	for i, a := range atoms {
+		if mask&(1<<uint(i)) != 0 {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func matchAtoms(p Predicate, atoms []Predicate, mask int) bool {
+	switch p := p.(type) {
+	case all:
+		for _, sub := range p {
+			if !matchAtoms(sub, atoms, mask) {
+				return false
+			}
+		}
+		return true
+	case anyOf:
+		for _, sub := range p {
+			if matchAtoms(sub, atoms, mask) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, a := range atoms {
+		if a == p {
+			return mask&(1<<uint(i)) != 0
+		}
+	}
+	return false
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// lcm returns false when the result doesn't fit below limit.
+func lcm(a, b, limit int) (int, bool) {
+	l := a / gcd(a, b)
+	if l > limit/b {
+		return 0, false
+	}
+	return l * b, true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// countUpTo counts the numbers in [0, n] matching every atom of set.
+func countUpTo(set []Predicate, n int) (int64, error) {
+	if n < 0 {
+		return 0, nil
+	}
+
+	onlyDivisibility := true
+	for _, a := range set {
+		switch a := a.(type) {
+		case divisibleBy:
+			if a.divisor == 0 {
+				return 0, nil
+			}
+		case contains:
+			onlyDivisibility = false
+			if !isValidBase(a.base) {
+				return 0, nil
+			}
+		case endsWith:
+			onlyDivisibility = false
+			if !isValidBase(a.base) {
+				return 0, nil
+			}
+		default:
+			onlyDivisibility = false
+		}
+	}
+	if onlyDivisibility {
+		return countMultiples(set, n), nil
+	}
+	return countDigits(set, n)
+}
+
+func countMultiples(set []Predicate, n int) int64 {
+	l := 1
+	for _, a := range set {
+		var ok bool
+		l, ok = lcm(l, abs(a.(divisibleBy).divisor), math.MaxInt64)
+		if !ok || l > n {
+			return 1
+		}
+	}
+	return int64(n/l) + 1
+}
+
+const digitAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+type pattern struct {
+	next  [][]int
+	found int
+}
+
+func newPattern(p string, base int) pattern {
+	failure := make([]int, len(p)+1)
+	for i := 1; i < len(p); i++ {
+		k := failure[i]
+		for k > 0 && p[k] != p[i] {
+			k = failure[k]
+		}
+		if p[k] == p[i] {
+			k++
+		}
+		failure[i+1] = k
+	}
+
+	pt := pattern{next: make([][]int, len(p)+1), found: len(p)}
+	for state := range pt.next {
+		pt.next[state] = make([]int, base)
+		for digit := 0; digit < base; digit++ {
+			if state == len(p) {
+				pt.next[state][digit] = state
+				continue
+			}
+			k := state
+			for k > 0 && p[k] != digitAlphabet[digit] {
+				k = failure[k]
+			}
+			if p[k] == digitAlphabet[digit] {
+				k++
+			}
+			pt.next[state][digit] = k
+		}
+	}
+	return pt
+}
+
+type digitState struct {
+	started   bool
+	remainder int
+	sum       int
+	patterns  string
+}
+
+// digitBase returns the base all containment and suffix atoms of set are
+// evaluated in.
+func digitBase(set []Predicate) (int, bool) {
+	base := 0
+	for _, a := range set {
+		b := 0
+		switch a := a.(type) {
+		case contains:
+			b = a.base
+		case endsWith:
+			b = a.base
+		case digitSum:
+			b = 10
+		default:
+			continue
+		}
+		if base != 0 && b != base {
+			return 0, false
+		}
+		base = b
+	}
+	if base == 0 {
+		base = 10
+	}
+	return base, true
+}
+
+// countDigits counts the numbers in [0, n] matching every atom of set by
+// walking the digits of n, tracking the remainder modulo the lcm of all
+// divisors and suffix moduli and the progress of every pattern.
+func countDigits(set []Predicate, n int) (int64, error) {
+	base, ok := digitBase(set)
+	if !ok {
+		return 0, ErrTooComplex
+	}
+
+	modulus := 1
+	maxSum := 0
+	var sums []int
+	var patterns []pattern
+	var accept []func(remainder int) bool
+	for _, a := range set {
+		var ok bool
+		switch a := a.(type) {
+		case divisibleBy:
+			d := abs(a.divisor)
+			modulus, ok = lcm(modulus, d, maxCountModulus)
+			accept = append(accept, func(remainder int) bool { return remainder%d == 0 })
+		case endsWith:
+			if a.suffix < 0 {
+				return 0, nil
+			}
+			m := 1
+			for range strconv.FormatInt(int64(a.suffix), base) {
+				if m > maxCountModulus/base {
+					return 0, ErrTooComplex
+				}
+				m *= base
+			}
+			suffix := a.suffix
+			modulus, ok = lcm(modulus, m, maxCountModulus)
+			accept = append(accept, func(remainder int) bool { return remainder%m == suffix })
+		case contains:
+			patterns = append(patterns, newPattern(strconv.FormatInt(int64(a.subNumber), base), base))
+			ok = true
+		case digitSum:
+			if a.sum < 0 {
+				return 0, nil
+			}
+			if a.sum > maxSum {
+				maxSum = a.sum
+			}
+			sums = append(sums, a.sum)
+			ok = true
+		}
+		if !ok {
+			return 0, ErrTooComplex
+		}
+	}
+
+	isMatch := func(s digitState) bool {
+		if !s.started {
+			return false
+		}
+		for _, ok := range accept {
+			if !ok(s.remainder) {
+				return false
+			}
+		}
+		for _, sum := range sums {
+			if s.sum != sum {
+				return false
+			}
+		}
+		for i, p := range patterns {
+			if int(s.patterns[i]) != p.found {
+				return false
+			}
+		}
+		return true
+	}
+
+	step := func(s digitState, digit int) digitState {
+		if !s.started && digit == 0 {
+			return s
+		}
+		progress := []byte(s.patterns)
+		for i, p := range patterns {
+			progress[i] = byte(p.next[progress[i]][digit])
+		}
+		sum := s.sum
+		if len(sums) > 0 && sum <= maxSum {
+			sum += digit
+		}
+		return digitState{true, (s.remainder*base + digit) % modulus, sum, string(progress)}
+	}
+
+	digits := strconv.FormatInt(int64(n), base)
+	tight := digitState{patterns: string(make([]byte, len(patterns)))}
+	free := make(map[digitState]int64)
+	for i := 0; i < len(digits); i++ {
+		limit := strings.IndexByte(digitAlphabet, digits[i])
+		nextFree := make(map[digitState]int64, len(free))
+		for s, count := range free {
+			for digit := 0; digit < base; digit++ {
+				nextFree[step(s, digit)] += count
+			}
+		}
+		for digit := 0; digit < limit; digit++ {
+			nextFree[step(tight, digit)]++
+		}
+		tight = step(tight, limit)
+		free = nextFree
+	}
+
+	var result int64
+	for s, count := range free {
+		if isMatch(s) {
+			result += count
+		}
+	}
+	if isMatch(tight) {
+		result++
+	}
+
+	zero := true
+	for _, a := range set {
+		if !a.Match(0) {
+			zero = false
+		}
+	}
+	if zero {
+		result++
+	}
+	return result, nil
+}
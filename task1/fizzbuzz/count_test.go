@@ -0,0 +1,120 @@
+package fizzbuzz
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func bruteForceCount(g *Game, from, to int) Counts {
+	counts := Counts{Words: make(map[string]int64)}
+	for number := from; number <= to; number++ {
+		word := g.Play(number)
+		used := g.policy.Combine(g.matched(number))
+		if len(used) == 0 {
+			counts.Numbers++
+		} else {
+			counts.Words[word]++
+		}
+		if number == to {
+			break
+		}
+	}
+	return counts
+}
+
+func TestCount(t *testing.T) {
+	games := map[string]*Game{
+		"classic": NewGame(DefaultRules()...),
+		"concatenate": NewGame().
+			AddRule(DivisibleBy(3), "fizz").
+			AddRule(DivisibleBy(5), "buzz").
+			AddRule(DivisibleBy(7), "whizz").
+			AddRule(Contains(3), "fizz").
+			SetPolicy(Concatenate),
+		"divisibility first": NewGame().
+			AddRule(DivisibleBy(4), "fizz").
+			AddRule(Contains(11), "buzz").
+			AddRule(EndsWith(25), "bang").
+			AddRule(Contains(0), "zero").
+			SetPolicy(DivisibilityFirst),
+		"priority": NewGame().
+			AddPriorityRule(DivisibleBy(6), "fizz", 1).
+			AddPriorityRule(All(Contains(12), EndsWith(2)), "buzz", 3).
+			AddPriorityRule(Contains(5), "five", 2).
+			SetPolicy(Priority),
+		"any and digit sum": NewGame().
+			AddRule(Any(DivisibleBy(3), Contains(3)), "fizz").
+			AddRule(All(DigitSum(10), Any(EndsWith(9), Contains(46))), "bang").
+			AddRule(DigitSum(7), "seven").
+			SetPolicy(Concatenate),
+		"zero divisor": NewGame().
+			AddRule(DivisibleBy(0), "zero").
+			AddRule(All(DivisibleBy(0), Contains(1)), "one").
+			AddRule(DivisibleBy(3), "fizz").
+			SetPolicy(Concatenate),
+	}
+
+	ranges := [][2]int{{0, 0}, {1, 100}, {0, 1000}, {37, 4321}, {9990, 12345}}
+
+	for name, game := range games {
+		for _, r := range ranges {
+			got, err := game.Count(r[0], r[1])
+			if err != nil {
+				t.Fatalf("%s got error %v but didn't want one", name, err)
+			}
+			assertCounts(t, got, bruteForceCount(game, r[0], r[1]))
+		}
+	}
+
+	t.Run("huge range", func(t *testing.T) {
+		got, err := NewGame(DefaultRules()...).Count(1, 1000000000000000000)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if got.Total() != 1000000000000000000 {
+			t.Errorf("got total %d, want %d", got.Total(), int64(1000000000000000000))
+		}
+	})
+
+	t.Run("max int boundary", func(t *testing.T) {
+		for name, game := range games {
+			got, err := game.Count(1, math.MaxInt64)
+			if err != nil {
+				t.Fatalf("%s got error %v but didn't want one", name, err)
+			}
+			if got.Total() != math.MaxInt64 {
+				t.Errorf("%s got total %d, want %d", name, got.Total(), int64(math.MaxInt64))
+			}
+			got, err = game.Count(math.MaxInt64-100, math.MaxInt64)
+			if err != nil {
+				t.Fatalf("%s got error %v but didn't want one", name, err)
+			}
+			assertCounts(t, got, bruteForceCount(game, math.MaxInt64-100, math.MaxInt64))
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		for _, r := range [][2]int{{5, 1}, {-1, 5}, {0, math.MaxInt64}} {
+			_, err := defaultGame.Count(r[0], r[1])
+			if err != ErrInvalidRange {
+				t.Errorf("%v got %v, want %v", r, err, ErrInvalidRange)
+			}
+		}
+	})
+
+	t.Run("func predicate", func(t *testing.T) {
+		game := NewGame().AddRule(Func("odd", func(n int) bool { return n%2 == 1 }), "odd")
+		_, err := game.Count(1, 10)
+		if err != ErrUnsupportedPredicate {
+			t.Errorf("got %v, want %v", err, ErrUnsupportedPredicate)
+		}
+	})
+}
+
+func assertCounts(t *testing.T, got, want Counts) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,168 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kind is the kind of check a condition does.
+type Kind string
+
+// condition kinds
+const (
+	KindDivisibility Kind = "divisibility"
+	KindContainment  Kind = "containment"
+	KindSuffix       Kind = "suffix"
+	KindDigitSum     Kind = "digit-sum"
+	KindCustom       Kind = "custom"
+)
+
+// Condition is one elementary check of a predicate, Operand is the
+// divisor, the contained number, the suffix or the digit sum.
+type Condition struct {
+	Kind        Kind   `json:"kind"`
+	Operand     int    `json:"operand"`
+	Description string `json:"description"`
+}
+
+func conditionsOf(p Predicate) []Condition {
+	switch p := p.(type) {
+	case divisibleBy:
+		return []Condition{{KindDivisibility, p.divisor, p.String()}}
+	case contains:
+		return []Condition{{KindContainment, p.subNumber, p.String()}}
+	case endsWith:
+		return []Condition{{KindSuffix, p.suffix, p.String()}}
+	case digitSum:
+		return []Condition{{KindDigitSum, p.sum, p.String()}}
+	case all:
+		var result []Condition
+		for _, sub := range p {
+			result = append(result, conditionsOf(sub)...)
+		}
+		return result
+	case anyOf:
+		var result []Condition
+		for _, sub := range p {
+			result = append(result, conditionsOf(sub)...)
+		}
+		return result
+	default:
+		return []Condition{{KindCustom, 0, p.String()}}
+	}
+}
+
+// Match is a rule of a game that matched a number, Rule is its index in
+// the game's rules and Used tells whether the policy used its word.
+type Match struct {
+	Rule       int         `json:"rule"`
+	Word       string      `json:"word"`
+	Predicate  string      `json:"predicate"`
+	Conditions []Condition `json:"conditions"`
+	Used       bool        `json:"used"`
+}
+
+// Explanation tells why a game gave a number its word.
+type Explanation struct {
+	Number  int     `json:"number"`
+	Word    string  `json:"word"`
+	Policy  string  `json:"policy"`
+	Matches []Match `json:"matches"`
+}
+
+// Explain evaluates number like Play and tells every rule that matched and
+// which of them the policy used.
+func (g *Game) Explain(number int) Explanation {
+	var matched []Rule
+	var indexes []int
+	for i, r := range g.rules {
+		if r.Predicate.Match(number) {
+			matched = append(matched, r)
+			indexes = append(indexes, i)
+		}
+	}
+
+	used := g.policy.Combine(matched)
+	e := Explanation{Number: number, Policy: g.policy.Name()}
+	if len(used) == 0 {
+		e.Word = g.render(number)
+	} else {
+		e.Word = g.joinWords(used)
+	}
+
+	isUsed := usedRules(matched, used)
+	for i, r := range matched {
+		e.Matches = append(e.Matches, Match{
+			Rule:       indexes[i],
+			Word:       r.Word,
+			Predicate:  r.Predicate.String(),
+			Conditions: conditionsOf(r.Predicate),
+			Used:       isUsed[i],
+		})
+	}
+	return e
+}
+
+// usedRules tells which of the matched rules are among the used ones.
+func usedRules(matched, used []Rule) []bool {
+	isUsed := make([]bool, len(matched))
+	for _, u := range used {
+		for i, r := range matched {
+			if !isUsed[i] && sameRule(r, u) {
+				isUsed[i] = true
+				break
+			}
+		}
+	}
+	return isUsed
+}
+
+func sameRule(a, b Rule) bool {
+	return a.Word == b.Word && a.Priority == b.Priority && a.Predicate.String() == b.Predicate.String()
+}
+
+func (e Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d: %s, decided by %s", e.Number, e.Word, e.Policy)
+	if len(e.Matches) == 0 {
+		sb.WriteString(", no rule matched")
+	}
+	for _, m := range e.Matches {
+		mark := "unused"
+		if m.Used {
+			mark = "used"
+		}
+		fmt.Fprintf(&sb, "\n  rule %d %s -> %s (%s)", m.Rule, m.Predicate, m.Word, mark)
+	}
+	return sb.String()
+}
+
+// WriteExplanations writes the explanations of [start, end] to w in text
+// or json format.
+func (g *Game) WriteExplanations(w io.Writer, start, end, step int, format Format) error {
+	if format != FormatText && format != FormatJSON {
+		return ErrUnknownFormat
+	}
+	it, err := g.Range(start, end, step)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(w, 64*1024)
+	encoder := json.NewEncoder(bw)
+	for number, ok := it.advance(); ok; number, ok = it.advance() {
+		e := g.Explain(number)
+		if format == FormatJSON {
+			err = encoder.Encode(e)
+		} else {
+			_, err = fmt.Fprintln(bw, e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
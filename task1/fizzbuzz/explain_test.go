@@ -0,0 +1,104 @@
+package fizzbuzz
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	t.Run("classic", func(t *testing.T) {
+		got := NewGame(DefaultRules()...).Explain(35)
+		want := Explanation{
+			Number: 35,
+			Word:   "fizzbuzz",
+			Policy: "first-match",
+			Matches: []Match{
+				{1, "fizzbuzz", "contains 3 and contains 5", []Condition{
+					{KindContainment, 3, "contains 3"},
+					{KindContainment, 5, "contains 5"},
+				}, true},
+				{3, "buzz", "divisible by 5", []Condition{{KindDivisibility, 5, "divisible by 5"}}, false},
+				{4, "fizz", "contains 3", []Condition{{KindContainment, 3, "contains 3"}}, false},
+				{5, "buzz", "contains 5", []Condition{{KindContainment, 5, "contains 5"}}, false},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("words agree with play", func(t *testing.T) {
+		game := NewGame().
+			AddRule(DivisibleBy(3), "fizz").
+			AddRule(EndsWith(5), "buzz").
+			AddRule(Func("even", func(n int) bool { return n%2 == 0 }), "even").
+			SetPolicy(DivisibilityFirst)
+		for number := 1; number <= 100; number++ {
+			got := game.Explain(number).Word
+			want := game.Play(number)
+			if got != want {
+				t.Errorf("%d got %s, want %s", number, got, want)
+			}
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		got := NewGame(DefaultRules()...).Explain(7).String()
+		want := "7: 7, decided by first-match, no rule matched"
+		assertString(t, got, want)
+	})
+}
+
+func TestWriteExplanations(t *testing.T) {
+	game := NewGame().
+		AddRule(DivisibleBy(3), "fizz").
+		AddRule(DivisibleBy(5), "buzz").
+		SetPolicy(Concatenate)
+
+	var buf bytes.Buffer
+	err := game.WriteExplanations(&buf, 14, 15, 1, FormatText)
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+	want := strings.Join([]string{
+		"14: 14, decided by concatenate, no rule matched",
+		"15: fizzbuzz, decided by concatenate",
+		"  rule 0 divisible by 3 -> fizz (used)",
+		"  rule 1 divisible by 5 -> buzz (used)",
+		"",
+	}, "\n")
+	assertString(t, buf.String(), want)
+
+	buf.Reset()
+	err = game.WriteExplanations(&buf, 3, 3, 1, FormatJSON)
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+	want = `{"number":3,"word":"fizz","policy":"concatenate","matches":[{"rule":0,"word":"fizz","predicate":"divisible by 3","conditions":[{"kind":"divisibility","operand":3,"description":"divisible by 3"}],"used":true}]}` + "\n"
+	assertString(t, buf.String(), want)
+
+	t.Run("evaluates each number once", func(t *testing.T) {
+		calls := 0
+		game := NewGame().AddRule(Func("counted", func(n int) bool {
+			calls++
+			return false
+		}), "counted")
+		err := game.WriteExplanations(ioutil.Discard, 1, 10, 1, FormatText)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if calls != 10 {
+			t.Errorf("got %d predicate calls, want 10", calls)
+		}
+	})
+}
+
+func assertString(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
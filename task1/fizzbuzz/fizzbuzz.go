@@ -1,49 +1,93 @@
 package fizzbuzz
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 )
 
 func isDivisibleBy(number, divisor int) bool {
-	return number%divisor == 0
+	return divisor != 0 && number%divisor == 0
 }
 
 func isContains(number, subNumber int) bool {
 	return strings.Contains(strconv.Itoa(number), strconv.Itoa(subNumber))
 }
 
+func isEndsWith(number, suffix int) bool {
+	return strings.HasSuffix(strconv.Itoa(number), strconv.Itoa(suffix))
+}
+
+func isDigitSum(number, sum int) bool {
+	total := 0
+	for _, digit := range strconv.Itoa(number) {
+		if digit != '-' {
+			total += int(digit - '0')
+		}
+	}
+	return total == sum
+}
+
+func isContainsIn(number, subNumber, base int) bool {
+	return strings.Contains(strconv.FormatInt(int64(number), base), strconv.FormatInt(int64(subNumber), base))
+}
+
+func isEndsWithIn(number, suffix, base int) bool {
+	return strings.HasSuffix(strconv.FormatInt(int64(number), base), strconv.FormatInt(int64(suffix), base))
+}
+
 // FizzBuzz magic number
 const (
 	FizzMagciNumber int = 3
 	BuzzMagicNumber int = 5
 )
 
-// FizzBuzz is a number game.
-func FizzBuzz(number int) string {
-	if isDivisibleBy(number, FizzMagciNumber) && isDivisibleBy(number, BuzzMagicNumber) {
-		return "fizzbuzz"
+// DefaultRules returns the rules of the classic fizzbuzz game.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Predicate: All(DivisibleBy(FizzMagciNumber), DivisibleBy(BuzzMagicNumber)), Word: "fizzbuzz"},
+		{Predicate: All(Contains(FizzMagciNumber), Contains(BuzzMagicNumber)), Word: "fizzbuzz"},
+		{Predicate: DivisibleBy(FizzMagciNumber), Word: "fizz"},
+		{Predicate: DivisibleBy(BuzzMagicNumber), Word: "buzz"},
+		{Predicate: Contains(FizzMagciNumber), Word: "fizz"},
+		{Predicate: Contains(BuzzMagicNumber), Word: "buzz"},
 	}
+}
 
-	if isContains(number, FizzMagciNumber) && isContains(number, BuzzMagicNumber) {
-		return "fizzbuzz"
-	}
+var defaultGame = NewGame(DefaultRules()...)
 
-	if isDivisibleBy(number, FizzMagciNumber) {
-		return "fizz"
-	}
+// FizzBuzz is a number game.
+func FizzBuzz(number int) string {
+	return defaultGame.Play(number)
+}
 
-	if isDivisibleBy(number, BuzzMagicNumber) {
-		return "buzz"
-	}
+// ErrUnknownRuleSet is returned when no rule set has the requested name.
+var ErrUnknownRuleSet = errors.New("unknown rule set")
 
-	if isContains(number, FizzMagciNumber) {
-		return "fizz"
-	}
+var ruleSets = map[string]func() *Game{
+	"classic": func() *Game {
+		return NewGame(DefaultRules()...)
+	},
+	"fizzbuzz": func() *Game {
+		return NewGame().
+			AddRule(DivisibleBy(FizzMagciNumber), "fizz").
+			AddRule(DivisibleBy(BuzzMagicNumber), "buzz").
+			SetPolicy(Concatenate)
+	},
+	"fizzbuzzwhizz": func() *Game {
+		return NewGame().
+			AddRule(DivisibleBy(FizzMagciNumber), "fizz").
+			AddRule(DivisibleBy(BuzzMagicNumber), "buzz").
+			AddRule(DivisibleBy(7), "whizz").
+			SetPolicy(Concatenate)
+	},
+}
 
-	if isContains(number, BuzzMagicNumber) {
-		return "buzz"
+// RuleSetByName returns a new game for one of the built-in rule sets,
+// "classic" is the FizzBuzz game.
+func RuleSetByName(name string) (*Game, error) {
+	if newGame, ok := ruleSets[name]; ok {
+		return newGame(), nil
 	}
-
-	return strconv.Itoa(number)
+	return nil, ErrUnknownRuleSet
 }
@@ -1,6 +1,9 @@
 package fizzbuzz
 
-import "testing"
+import (
+	"strconv"
+	"testing"
+)
 
 func TestFizzBuzz(t *testing.T) {
 	fizzBuzzTests := []struct {
@@ -25,3 +28,157 @@ func TestFizzBuzz(t *testing.T) {
 		}
 	}
 }
+
+func TestGame(t *testing.T) {
+	t.Run("custom rules", func(t *testing.T) {
+		game := NewGame().
+			AddRule(DivisibleBy(7), "whizz").
+			AddRule(EndsWith(9), "bang").
+			AddRule(Func("is 11", func(n int) bool { return n == 11 }), "eleven")
+
+		gameTests := []struct {
+			number int
+			result string
+		}{
+			{1, "1"},
+			{14, "whizz"},
+			{19, "bang"},
+			{49, "whizz"},
+			{11, "eleven"},
+			{90, "90"},
+		}
+
+		for _, tt := range gameTests {
+			got := game.Play(tt.number)
+			if got != tt.result {
+				t.Errorf("%d got %s, want %s", tt.number, got, tt.result)
+			}
+		}
+	})
+
+	t.Run("zero divisor never matches", func(t *testing.T) {
+		game := NewGame().AddRule(DivisibleBy(0), "zero")
+		for _, number := range []int{0, 1, 5} {
+			want := strconv.Itoa(number)
+			if got := game.Play(number); got != want {
+				t.Errorf("%d got %s, want %s", number, got, want)
+			}
+		}
+	})
+
+	t.Run("default rules agree with fizzbuzz", func(t *testing.T) {
+		game := NewGame(DefaultRules()...)
+		for number := 1; number <= 1000; number++ {
+			got := game.Play(number)
+			want := FizzBuzz(number)
+			if got != want {
+				t.Errorf("%d got %s, want %s", number, got, want)
+			}
+		}
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	newVariant := func(p Policy) *Game {
+		return NewGame().
+			AddRule(DivisibleBy(3), "fizz").
+			AddRule(DivisibleBy(5), "buzz").
+			AddRule(DivisibleBy(7), "whizz").
+			AddRule(Contains(3), "fizz").
+			AddRule(Contains(5), "buzz").
+			SetPolicy(p)
+	}
+
+	policyTests := []struct {
+		policy Policy
+		number int
+		result string
+	}{
+		{FirstMatch, 15, "fizz"},
+		{FirstMatch, 13, "fizz"},
+		{FirstMatch, 52, "buzz"},
+		{Concatenate, 15, "fizzbuzzbuzz"},
+		{Concatenate, 105, "fizzbuzzwhizzbuzz"},
+		{Concatenate, 35, "buzzwhizzfizzbuzz"},
+		{Concatenate, 1, "1"},
+		{DivisibilityFirst, 15, "fizzbuzz"},
+		{DivisibilityFirst, 35, "buzzwhizz"},
+		{DivisibilityFirst, 53, "fizzbuzz"},
+		{DivisibilityFirst, 2, "2"},
+	}
+
+	for _, tt := range policyTests {
+		got := newVariant(tt.policy).Play(tt.number)
+		if got != tt.result {
+			t.Errorf("%s %d got %s, want %s", tt.policy.Name(), tt.number, got, tt.result)
+		}
+	}
+
+	t.Run("concatenate divisors", func(t *testing.T) {
+		game := NewGame().
+			AddRule(DivisibleBy(3), "fizz").
+			AddRule(DivisibleBy(5), "buzz").
+			AddRule(DivisibleBy(7), "whizz").
+			SetPolicy(Concatenate)
+
+		concatenateTests := []struct {
+			number int
+			result string
+		}{
+			{15, "fizzbuzz"},
+			{21, "fizzwhizz"},
+			{105, "fizzbuzzwhizz"},
+			{11, "11"},
+		}
+
+		for _, tt := range concatenateTests {
+			got := game.Play(tt.number)
+			if got != tt.result {
+				t.Errorf("%d got %s, want %s", tt.number, got, tt.result)
+			}
+		}
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		game := NewGame().
+			AddPriorityRule(DivisibleBy(3), "fizz", 1).
+			AddPriorityRule(DivisibleBy(5), "buzz", 2).
+			AddPriorityRule(Contains(3), "three", 2).
+			SetPolicy(Priority)
+
+		priorityTests := []struct {
+			number int
+			result string
+		}{
+			{15, "buzz"},
+			{9, "fizz"},
+			{33, "three"},
+			{35, "buzz"},
+			{7, "7"},
+		}
+
+		for _, tt := range priorityTests {
+			got := game.Play(tt.number)
+			if got != tt.result {
+				t.Errorf("%d got %s, want %s", tt.number, got, tt.result)
+			}
+		}
+	})
+
+	t.Run("policy by name", func(t *testing.T) {
+		for _, p := range []Policy{FirstMatch, Concatenate, DivisibilityFirst, Priority} {
+			got, err := PolicyByName(p.Name())
+			if err != nil {
+				t.Fatalf("got error %v but didn't want one", err)
+			}
+			if got != p {
+				t.Errorf("got %s, want %s", got.Name(), p.Name())
+			}
+		}
+
+		_, err := PolicyByName("last-match")
+		if err != ErrUnknownPolicy {
+			t.Errorf("got %v, want %v", err, ErrUnknownPolicy)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+const maxTableSize = 1 << 16
+
+// Generator evaluates ranges in chunks across a pool of workers and writes
+// the results in order.
+type Generator struct {
+	game      *Game
+	workers   int
+	chunkSize int
+	table     *periodTable
+}
+
+// NewGenerator creates a generator for game, workers and chunkSize below 1
+// fall back to the number of CPUs and 64k numbers. The game must not be
+// changed while the generator is used.
+func NewGenerator(game *Game, workers, chunkSize int) *Generator {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if chunkSize < 1 {
+		chunkSize = 64 * 1024
+	}
+	return &Generator{
+		game:      game,
+		workers:   workers,
+		chunkSize: chunkSize,
+		table:     newPeriodTable(game),
+	}
+}
+
+// periodTable holds the words of a game whose rules only check
+// divisibility, they repeat with the lcm of all divisors.
+type periodTable struct {
+	words []string
+	used  []bool
+}
+
+func newPeriodTable(g *Game) *periodTable {
+	period := 1
+	for _, r := range g.rules {
+		if !isDivisibility(r.Predicate) {
+			return nil
+		}
+		for _, c := range conditionsOf(r.Predicate) {
+			if c.Operand == 0 {
+				return nil
+			}
+			var ok bool
+			period, ok = lcm(period, abs(c.Operand), maxTableSize)
+			if !ok {
+				return nil
+			}
+		}
+	}
+
+	t := &periodTable{make([]string, period), make([]bool, period)}
+	for residue := 0; residue < period; residue++ {
+		matched := g.matched(residue)
+		used := g.policy.Combine(matched)
+		if len(used) > 0 {
+			t.words[residue] = g.joinWords(used)
+			t.used[residue] = true
+		}
+	}
+	return t
+}
+
+func (t *periodTable) residue(number int) int {
+	period := len(t.words)
+	return (number%period + period) % period
+}
+
+type chunk struct {
+	first  int
+	count  int
+	result chan []byte
+}
+
+// WriteRange writes the same output as Game.WriteRange.
+func (gen *Generator) WriteRange(w io.Writer, start, end, step int, format Format) error {
+	if _, err := newResultWriter(format); err != nil {
+		return err
+	}
+	if step == 0 {
+		return ErrInvalidStep
+	}
+	if (step > 0 && start > end) || (step < 0 && start < end) {
+		return nil
+	}
+
+	// last is the index of the last number, the count last+1 wraps to 0
+	// for the whole int range with a step of 1.
+	var last uint64
+	if step > 0 {
+		last = (uint64(end) - uint64(start)) / uint64(step)
+	} else {
+		last = (uint64(start) - uint64(end)) / (-uint64(step))
+	}
+
+	pool := &sync.Pool{New: func() interface{} { return []byte(nil) }}
+	jobs := make(chan chunk)
+	ordered := make(chan chunk, gen.workers*2)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(jobs)
+		defer close(ordered)
+		size := uint64(gen.chunkSize)
+		for index := uint64(0); ; index += size {
+			count := size
+			if last-index < size {
+				count = last - index + 1
+			}
+			c := chunk{start + int(index)*step, int(count), make(chan []byte, 1)}
+			select {
+			case ordered <- c:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- c:
+			case <-done:
+				return
+			}
+			if last-index < size {
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < gen.workers; i++ {
+		go func() {
+			for c := range jobs {
+				c.result <- gen.evaluate(pool.Get().([]byte)[:0], c, step, format)
+			}
+		}()
+	}
+
+	bw := bufio.NewWriterSize(w, 256*1024)
+	for c := range ordered {
+		out := <-c.result
+		_, err := bw.Write(out)
+		pool.Put(out)
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// evaluate appends the results of c to out.
+func (gen *Generator) evaluate(out []byte, c chunk, step int, format Format) []byte {
+	g := gen.game
+	if format == FormatText && gen.table != nil && g.renderer == Decimal {
+		return gen.evaluateTable(out, c, step)
+	}
+
+	write, _ := newResultWriter(format)
+	buf := bytes.NewBuffer(out)
+	bw := bufio.NewWriter(buf)
+	number := c.first
+	for i := 0; i < c.count; i++ {
+		var word string
+		if gen.table != nil {
+			if residue := gen.table.residue(number); gen.table.used[residue] {
+				word = gen.table.words[residue]
+			} else {
+				word = g.render(number)
+			}
+		} else {
+			word = g.Play(number)
+		}
+		write(bw, Result{number, word})
+		number += step
+	}
+	bw.Flush()
+	return buf.Bytes()
+}
+
+// evaluateTable writes text results walking the period table by residue
+// so numbers need neither modulo nor strconv unless no rule is used.
+func (gen *Generator) evaluateTable(out []byte, c chunk, step int) []byte {
+	t := gen.table
+	period := len(t.words)
+	residue := t.residue(c.first)
+	stepResidue := t.residue(step)
+	number := c.first
+	for i := 0; i < c.count; i++ {
+		if t.used[residue] {
+			out = append(out, t.words[residue]...)
+		} else {
+			out = strconv.AppendInt(out, int64(number), 10)
+		}
+		out = append(out, '\n')
+
+		number += step
+		residue += stepResidue
+		if residue >= period {
+			residue -= period
+		}
+	}
+	return out
+}
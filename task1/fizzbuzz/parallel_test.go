@@ -0,0 +1,137 @@
+package fizzbuzz
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	games := map[string]*Game{
+		"classic":  NewGame(DefaultRules()...),
+		"table":    NewGame().AddRule(DivisibleBy(3), "fizz").AddRule(DivisibleBy(5), "buzz").SetPolicy(Concatenate),
+		"roman":    NewGame().AddRule(DivisibleBy(3), "fizz").SetRenderer(Roman),
+		"combined": NewGame().AddRule(All(DivisibleBy(4), DivisibleBy(6)), "twelve").AddRule(DivisibleBy(7), "seven"),
+	}
+
+	rangeTests := []struct {
+		start, end, step int
+	}{
+		{1, 1000, 1},
+		{-50, 50, 3},
+		{1000, 1, -7},
+		{5, 1, 1},
+		{math.MaxInt64 - 100, math.MaxInt64, 9},
+		{math.MinInt64, math.MaxInt64, math.MaxInt64},
+		{math.MaxInt64, math.MinInt64, math.MinInt64},
+		{-5, math.MaxInt64, math.MaxInt64 / 2},
+	}
+
+	for name, game := range games {
+		for _, format := range []Format{FormatText, FormatCSV, FormatJSON} {
+			for _, tt := range rangeTests {
+				var want, got bytes.Buffer
+				if err := game.WriteRange(&want, tt.start, tt.end, tt.step, format); err != nil {
+					t.Fatalf("got error %v but didn't want one", err)
+				}
+				gen := NewGenerator(game, 3, 17)
+				if err := gen.WriteRange(&got, tt.start, tt.end, tt.step, format); err != nil {
+					t.Fatalf("got error %v but didn't want one", err)
+				}
+				if got.String() != want.String() {
+					t.Errorf("%s %s %v got %.60q, want %.60q", name, format, tt, got.String(), want.String())
+				}
+			}
+		}
+	}
+
+	t.Run("period table", func(t *testing.T) {
+		if NewGenerator(games["table"], 0, 0).table == nil {
+			t.Errorf("got no period table for divisibility rules")
+		}
+		if NewGenerator(games["classic"], 0, 0).table != nil {
+			t.Errorf("got a period table for containment rules")
+		}
+	})
+
+	t.Run("whole int range", func(t *testing.T) {
+		for _, tt := range []struct {
+			start, end, step int
+			want             string
+		}{
+			{math.MinInt64, math.MaxInt64, 1, "-9223372036854775808\n-9223372036854775807\nfizz\n"},
+			{math.MaxInt64, math.MinInt64, -1, "9223372036854775807\nfizz\nbuzz\n"},
+		} {
+			w := &limitedWriter{limit: len(tt.want)}
+			err := NewGenerator(games["table"], 2, 3).WriteRange(w, tt.start, tt.end, tt.step, FormatText)
+			if err != errLimitReached {
+				t.Errorf("got %v, want %v", err, errLimitReached)
+			}
+			assertString(t, w.String(), tt.want)
+
+			w = &limitedWriter{limit: len(tt.want)}
+			err = games["table"].WriteRange(w, tt.start, tt.end, tt.step, FormatText)
+			if err != errLimitReached {
+				t.Errorf("got %v, want %v", err, errLimitReached)
+			}
+			assertString(t, w.String(), tt.want)
+		}
+	})
+
+	t.Run("zero step", func(t *testing.T) {
+		err := NewGenerator(games["classic"], 0, 0).WriteRange(&bytes.Buffer{}, 1, 2, 0, FormatText)
+		if err != ErrInvalidStep {
+			t.Errorf("got %v, want %v", err, ErrInvalidStep)
+		}
+	})
+}
+
+var errLimitReached = errors.New("limit reached")
+
+// limitedWriter keeps the first limit bytes written to it and fails after.
+type limitedWriter struct {
+	bytes.Buffer
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if n := w.limit - w.Len(); n < len(p) {
+		w.Buffer.Write(p[:n])
+		return n, errLimitReached
+	}
+	return w.Buffer.Write(p)
+}
+
+const benchmarkRange = 1000000
+
+func BenchmarkFizzBuzz(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for number := 1; number <= benchmarkRange; number++ {
+			FizzBuzz(number)
+		}
+	}
+}
+
+func BenchmarkWriteRange(b *testing.B) {
+	game := NewGame(DefaultRules()...)
+	for i := 0; i < b.N; i++ {
+		game.WriteRange(ioutil.Discard, 1, benchmarkRange, 1, FormatText)
+	}
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	gen := NewGenerator(NewGame(DefaultRules()...), 0, 0)
+	for i := 0; i < b.N; i++ {
+		gen.WriteRange(ioutil.Discard, 1, benchmarkRange, 1, FormatText)
+	}
+}
+
+func BenchmarkGeneratorTable(b *testing.B) {
+	game := NewGame().AddRule(DivisibleBy(3), "fizz").AddRule(DivisibleBy(5), "buzz").SetPolicy(Concatenate)
+	gen := NewGenerator(game, 0, 0)
+	for i := 0; i < b.N; i++ {
+		gen.WriteRange(ioutil.Discard, 1, benchmarkRange, 1, FormatText)
+	}
+}
@@ -0,0 +1,164 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mgxian/tdd-practice/other/players"
+)
+
+// ErrNotEnoughPlayers is returned when a party starts with fewer than two
+// players.
+var ErrNotEnoughPlayers = errors.New("a party needs at least two players")
+
+// ErrPartyOver is returned when a party has a winner and nobody is left to
+// take a turn.
+var ErrPartyOver = errors.New("the party is over")
+
+// Party is a fizzbuzz game where players take turns answering the next
+// number, a wrong answer eliminates the player.
+type Party struct {
+	game    *Game
+	players []string
+	turn    int
+	number  int
+}
+
+// NewParty starts a party at number 1 with at least two players in turn
+// order.
+func NewParty(game *Game, players ...string) (*Party, error) {
+	if len(players) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+	return &Party{
+		game:    game,
+		players: append([]string(nil), players...),
+		number:  1,
+	}, nil
+}
+
+// Players returns the players still in the party in turn order.
+func (p *Party) Players() []string {
+	return append([]string(nil), p.players...)
+}
+
+// Over tells whether only the winner is left.
+func (p *Party) Over() bool {
+	return len(p.players) < 2
+}
+
+// Current returns the player whose turn it is.
+func (p *Party) Current() (string, error) {
+	if p.Over() {
+		return "", ErrPartyOver
+	}
+	return p.players[p.turn], nil
+}
+
+// Number returns the number to answer.
+func (p *Party) Number() int {
+	return p.number
+}
+
+// Expected returns the right answer for the number.
+func (p *Party) Expected() string {
+	return p.game.Play(p.number)
+}
+
+// Answer checks the current player's answer, eliminates the player when
+// it is wrong and moves on to the next player and number.
+func (p *Party) Answer(answer string) (bool, error) {
+	if p.Over() {
+		return false, ErrPartyOver
+	}
+	correct := strings.EqualFold(strings.TrimSpace(answer), p.Expected())
+	if correct {
+		p.turn++
+	} else {
+		p.players = append(p.players[:p.turn], p.players[p.turn+1:]...)
+	}
+	if p.turn >= len(p.players) {
+		p.turn = 0
+	}
+	p.number++
+	return correct, nil
+}
+
+// Winner returns the last player standing once the party is over.
+func (p *Party) Winner() (string, bool) {
+	if !p.Over() {
+		return "", false
+	}
+	return p.players[0], true
+}
+
+// PartyCLI plays a party over a terminal and records the winner.
+type PartyCLI struct {
+	game        *Game
+	playerStore players.PlayerStore
+	in          *bufio.Scanner
+	out         io.Writer
+}
+
+// PlayerPrompt asks for the players of a party.
+const PlayerPrompt = "Please enter the players separated by spaces: "
+
+// NewPartyCLI creates a terminal party for game, store may be nil when
+// winners aren't recorded.
+func NewPartyCLI(game *Game, store players.PlayerStore, in io.Reader, out io.Writer) *PartyCLI {
+	return &PartyCLI{
+		game:        game,
+		playerStore: store,
+		in:          bufio.NewScanner(in),
+		out:         out,
+	}
+}
+
+// PlayParty asks for at least two players and plays until one of them is
+// left or the input ends.
+func (cli *PartyCLI) PlayParty() {
+	fmt.Fprint(cli.out, PlayerPrompt)
+	line, ok := cli.readline()
+	if !ok {
+		return
+	}
+	party, err := NewParty(cli.game, strings.Fields(line)...)
+	if err != nil {
+		fmt.Fprintln(cli.out, err)
+		return
+	}
+
+	for {
+		if winner, ok := party.Winner(); ok {
+			fmt.Fprintf(cli.out, "%s wins!\n", winner)
+			if cli.playerStore != nil {
+				cli.playerStore.RecordWin(winner)
+			}
+			return
+		}
+
+		player, err := party.Current()
+		if err != nil {
+			return
+		}
+		number, expected := party.Number(), party.Expected()
+		fmt.Fprintf(cli.out, "%s, %d? ", player, number)
+		answer, ok := cli.readline()
+		if !ok {
+			return
+		}
+		if correct, _ := party.Answer(answer); !correct {
+			fmt.Fprintf(cli.out, "wrong, %d is %s, %s is out\n", number, expected, player)
+		}
+	}
+}
+
+func (cli *PartyCLI) readline() (string, bool) {
+	if !cli.in.Scan() {
+		return "", false
+	}
+	return cli.in.Text(), true
+}
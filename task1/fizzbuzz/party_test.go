@@ -0,0 +1,99 @@
+package fizzbuzz_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mgxian/tdd-practice/other/players"
+	"github.com/mgxian/tdd-practice/task1/fizzbuzz"
+)
+
+func TestParty(t *testing.T) {
+	party, err := fizzbuzz.NewParty(fizzbuzz.NewGame(fizzbuzz.DefaultRules()...), "Chris", "Cleo", "Tiest")
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+
+	turns := []struct {
+		player  string
+		answer  string
+		correct bool
+	}{
+		{"Chris", "1", true},
+		{"Cleo", "2", true},
+		{"Tiest", "3", false},
+		{"Chris", "4", true},
+		{"Cleo", "Buzz", true},
+		{"Chris", "fizz", true},
+		{"Cleo", "fizz", false},
+	}
+
+	for _, tt := range turns {
+		if got, err := party.Current(); err != nil || got != tt.player {
+			t.Fatalf("got turn of %s %v, want %s", got, err, tt.player)
+		}
+		if got, err := party.Answer(tt.answer); err != nil || got != tt.correct {
+			t.Errorf("%s answering %s got %v %v, want %v", tt.player, tt.answer, got, err, tt.correct)
+		}
+	}
+
+	winner, ok := party.Winner()
+	if !ok || winner != "Chris" {
+		t.Errorf("got winner %q %v, want Chris", winner, ok)
+	}
+	if got, want := party.Players(), []string{"Chris"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	t.Run("over after the last elimination", func(t *testing.T) {
+		if _, err := party.Current(); err != fizzbuzz.ErrPartyOver {
+			t.Errorf("got %v, want %v", err, fizzbuzz.ErrPartyOver)
+		}
+		if _, err := party.Answer("8"); err != fizzbuzz.ErrPartyOver {
+			t.Errorf("got %v, want %v", err, fizzbuzz.ErrPartyOver)
+		}
+		if got, want := party.Players(), []string{"Chris"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("not enough players", func(t *testing.T) {
+		for _, names := range [][]string{nil, {"Chris"}} {
+			if _, err := fizzbuzz.NewParty(fizzbuzz.NewGame(), names...); err != fizzbuzz.ErrNotEnoughPlayers {
+				t.Errorf("%v got %v, want %v", names, err, fizzbuzz.ErrNotEnoughPlayers)
+			}
+		}
+	})
+}
+
+func TestPartyCLI(t *testing.T) {
+	t.Run("record the winner", func(t *testing.T) {
+		in := strings.NewReader("Chris Cleo\n1\n2\nfizz\nfive\n")
+		out := &bytes.Buffer{}
+		playerStore := &players.StubPlayerStore{}
+
+		cli := fizzbuzz.NewPartyCLI(fizzbuzz.NewGame(fizzbuzz.DefaultRules()...), playerStore, in, out)
+		cli.PlayParty()
+
+		players.AssertPlayerWin(t, playerStore, "Chris")
+		want := fizzbuzz.PlayerPrompt +
+			"Chris, 1? Cleo, 2? Chris, 3? Cleo, 4? wrong, 4 is 4, Cleo is out\nChris wins!\n"
+		if out.String() != want {
+			t.Errorf("got %q, want %q", out.String(), want)
+		}
+	})
+
+	t.Run("not enough players", func(t *testing.T) {
+		in := strings.NewReader("Chris\n")
+		out := &bytes.Buffer{}
+
+		fizzbuzz.NewPartyCLI(fizzbuzz.NewGame(), nil, in, out).PlayParty()
+
+		want := fizzbuzz.PlayerPrompt + "a party needs at least two players\n"
+		if out.String() != want {
+			t.Errorf("got %q, want %q", out.String(), want)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package fizzbuzz
+
+import "errors"
+
+// ErrUnknownPolicy is returned when no policy has the requested name.
+var ErrUnknownPolicy = errors.New("unknown combination policy")
+
+// Policy combines the rules matching a number into the rules that make up
+// its word. Matched rules are given in registration order.
+type Policy interface {
+	Name() string
+	Combine(matched []Rule) []Rule
+}
+
+type firstMatch struct{}
+
+func (firstMatch) Name() string {
+	return "first-match"
+}
+
+func (firstMatch) Combine(matched []Rule) []Rule {
+	if len(matched) == 0 {
+		return nil
+	}
+	return matched[:1]
+}
+
+type concatenate struct{}
+
+func (concatenate) Name() string {
+	return "concatenate"
+}
+
+func (concatenate) Combine(matched []Rule) []Rule {
+	return matched
+}
+
+type divisibilityFirst struct{}
+
+func (divisibilityFirst) Name() string {
+	return "divisibility-first"
+}
+
+func (divisibilityFirst) Combine(matched []Rule) []Rule {
+	var divisible, others []Rule
+	for _, r := range matched {
+		if isDivisibility(r.Predicate) {
+			divisible = append(divisible, r)
+		} else {
+			others = append(others, r)
+		}
+	}
+	if len(divisible) > 0 {
+		return divisible
+	}
+	return others
+}
+
+type priority struct{}
+
+func (priority) Name() string {
+	return "priority"
+}
+
+func (priority) Combine(matched []Rule) []Rule {
+	if len(matched) == 0 {
+		return nil
+	}
+	best := 0
+	for i, r := range matched {
+		if r.Priority > matched[best].Priority {
+			best = i
+		}
+	}
+	return matched[best : best+1]
+}
+
+// Combination policies
+var (
+	// FirstMatch uses the first matching rule.
+	FirstMatch Policy = firstMatch{}
+	// Concatenate joins the words of every matching rule.
+	Concatenate Policy = concatenate{}
+	// DivisibilityFirst joins the words of matching divisibility rules,
+	// falling back to the other matching rules when none of them match.
+	DivisibilityFirst Policy = divisibilityFirst{}
+	// Priority uses the matching rule with the highest priority, the
+	// earliest registered one wins a tie.
+	Priority Policy = priority{}
+)
+
+var policies = []Policy{FirstMatch, Concatenate, DivisibilityFirst, Priority}
+
+// PolicyByName returns the built-in policy called name.
+func PolicyByName(name string) (Policy, error) {
+	for _, p := range policies {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, ErrUnknownPolicy
+}
+
+func isDivisibility(p Predicate) bool {
+	switch p := p.(type) {
+	case divisibleBy:
+		return true
+	case all:
+		for _, sub := range p {
+			if !isDivisibility(sub) {
+				return false
+			}
+		}
+		return len(p) > 0
+	case anyOf:
+		for _, sub := range p {
+			if !isDivisibility(sub) {
+				return false
+			}
+		}
+		return len(p) > 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,165 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// ErrInvalidStep is returned when a range step is zero.
+var ErrInvalidStep = errors.New("range step must not be zero")
+
+// ErrUnknownFormat is returned when an output format is not supported.
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Result is the word a game gives a number.
+type Result struct {
+	Number int    `json:"number"`
+	Word   string `json:"word"`
+}
+
+// Iterator walks the numbers of a range lazily.
+type Iterator struct {
+	game    *Game
+	next    int
+	end     int
+	step    int
+	done    bool
+	current Result
+}
+
+// Range returns an iterator over [start, end] moving by step, a negative
+// step walks downwards.
+func (g *Game) Range(start, end, step int) (*Iterator, error) {
+	if step == 0 {
+		return nil, ErrInvalidStep
+	}
+	it := &Iterator{game: g, next: start, end: end, step: step}
+	it.done = (step > 0 && start > end) || (step < 0 && start < end)
+	return it, nil
+}
+
+// Next advances to the next number, it returns false when the range is over.
+func (it *Iterator) Next() bool {
+	number, ok := it.advance()
+	if !ok {
+		return false
+	}
+	it.current = Result{number, it.game.Play(number)}
+	return true
+}
+
+// advance moves to the next number without playing it.
+func (it *Iterator) advance() (int, bool) {
+	if it.done {
+		return 0, false
+	}
+	// the distance left can exceed MaxInt64, so it is measured in uint64
+	number := it.next
+	if it.step > 0 {
+		it.done = uint64(it.end)-uint64(number) < uint64(it.step)
+	} else {
+		it.done = uint64(number)-uint64(it.end) < -uint64(it.step)
+	}
+	if !it.done {
+		it.next = number + it.step
+	}
+	return number, true
+}
+
+// Result returns the current result.
+func (it *Iterator) Result() Result {
+	return it.current
+}
+
+// Stream sends the results of [start, end] on the returned channel, which
+// is closed after the last one or once ctx is done.
+func (g *Game) Stream(ctx context.Context, start, end, step int) (<-chan Result, error) {
+	it, err := g.Range(start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	results := make(chan Result, 1024)
+	go func() {
+		defer close(results)
+		for it.Next() {
+			select {
+			case results <- it.Result():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+// Format is an output format of WriteRange.
+type Format string
+
+// output formats
+const (
+	FormatText Format = "text"
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+type resultWriter func(w *bufio.Writer, r Result) error
+
+func newResultWriter(format Format) (resultWriter, error) {
+	switch format {
+	case FormatText:
+		return writeText, nil
+	case FormatCSV:
+		return writeCSV, nil
+	case FormatJSON:
+		return writeJSON, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func writeText(w *bufio.Writer, r Result) error {
+	w.WriteString(r.Word)
+	return w.WriteByte('\n')
+}
+
+func writeCSV(w *bufio.Writer, r Result) error {
+	var buf [20]byte
+	w.Write(strconv.AppendInt(buf[:0], int64(r.Number), 10))
+	w.WriteByte(',')
+	w.WriteString(r.Word)
+	return w.WriteByte('\n')
+}
+
+func writeJSON(w *bufio.Writer, r Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	w.Write(data)
+	return w.WriteByte('\n')
+}
+
+// WriteRange writes the results of [start, end] to w one per line without
+// holding them in memory.
+func (g *Game) WriteRange(w io.Writer, start, end, step int, format Format) error {
+	write, err := newResultWriter(format)
+	if err != nil {
+		return err
+	}
+	it, err := g.Range(start, end, step)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(w, 64*1024)
+	for it.Next() {
+		if err := write(bw, it.Result()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
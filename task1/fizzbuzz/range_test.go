@@ -0,0 +1,138 @@
+package fizzbuzz
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	game := NewGame(DefaultRules()...)
+
+	rangeTests := []struct {
+		name             string
+		start, end, step int
+		want             []Result
+	}{
+		{"ascending", 1, 5, 1, []Result{{1, "1"}, {2, "2"}, {3, "fizz"}, {4, "4"}, {5, "buzz"}}},
+		{"step", 1, 10, 4, []Result{{1, "1"}, {5, "buzz"}, {9, "fizz"}}},
+		{"descending", 16, 14, -1, []Result{{16, "16"}, {15, "fizzbuzz"}, {14, "14"}}},
+		{"empty", 5, 1, 1, nil},
+		{"near max int", math.MaxInt64 - 1, math.MaxInt64, 1, []Result{
+			{math.MaxInt64 - 1, game.Play(math.MaxInt64 - 1)},
+			{math.MaxInt64, game.Play(math.MaxInt64)},
+		}},
+		{"whole int range", math.MinInt64, math.MaxInt64, math.MaxInt64, []Result{
+			{math.MinInt64, game.Play(math.MinInt64)},
+			{-1, "-1"},
+			{math.MaxInt64 - 1, game.Play(math.MaxInt64 - 1)},
+		}},
+		{"whole int range descending", math.MaxInt64, math.MinInt64, math.MinInt64, []Result{
+			{math.MaxInt64, game.Play(math.MaxInt64)},
+			{-1, "-1"},
+		}},
+		{"wider than max int", -5, math.MaxInt64, math.MaxInt64 / 2, []Result{
+			{-5, "buzz"},
+			{math.MaxInt64/2 - 5, game.Play(math.MaxInt64/2 - 5)},
+			{math.MaxInt64 - 6, game.Play(math.MaxInt64 - 6)},
+		}},
+	}
+
+	for _, tt := range rangeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := game.Range(tt.start, tt.end, tt.step)
+			if err != nil {
+				t.Fatalf("got error %v but didn't want one", err)
+			}
+			var got []Result
+			for it.Next() {
+				got = append(got, it.Result())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("whole int range by one", func(t *testing.T) {
+		it, err := game.Range(math.MinInt64, math.MaxInt64, 1)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		var got []int
+		for len(got) < 3 && it.Next() {
+			got = append(got, it.Result().Number)
+		}
+		want := []int{math.MinInt64, math.MinInt64 + 1, math.MinInt64 + 2}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("zero step", func(t *testing.T) {
+		_, err := game.Range(1, 10, 0)
+		if err != ErrInvalidStep {
+			t.Errorf("got %v, want %v", err, ErrInvalidStep)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		results, err := game.Stream(context.Background(), 13, 15, 1)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		var got []Result
+		for r := range results {
+			got = append(got, r)
+		}
+		want := []Result{{13, "fizz"}, {14, "14"}, {15, "fizzbuzz"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("stream cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		results, err := game.Stream(ctx, 1, math.MaxInt64, 1)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if r := <-results; r.Number != 1 {
+			t.Errorf("got %v, want number 1", r)
+		}
+		cancel()
+		for range results {
+		}
+	})
+}
+
+func TestWriteRange(t *testing.T) {
+	game := NewGame(DefaultRules()...)
+
+	writeTests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatText, "fizz\n4\nbuzz\n"},
+		{FormatCSV, "3,fizz\n4,4\n5,buzz\n"},
+		{FormatJSON, "{\"number\":3,\"word\":\"fizz\"}\n{\"number\":4,\"word\":\"4\"}\n{\"number\":5,\"word\":\"buzz\"}\n"},
+	}
+
+	for _, tt := range writeTests {
+		var buf bytes.Buffer
+		err := game.WriteRange(&buf, 3, 5, 1, tt.format)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s got %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+
+	err := game.WriteRange(&bytes.Buffer{}, 3, 5, 1, Format("xml"))
+	if err != ErrUnknownFormat {
+		t.Errorf("got %v, want %v", err, ErrUnknownFormat)
+	}
+}
@@ -0,0 +1,189 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownRenderer is returned when no renderer has the requested name.
+var ErrUnknownRenderer = errors.New("unknown number renderer")
+
+// Renderer writes the numbers no rule is used for.
+type Renderer interface {
+	Render(number int) string
+}
+
+// ErrInvalidBase is returned when a base is outside 2 to 36.
+var ErrInvalidBase = errors.New("base must be between 2 and 36")
+
+// Base renders numbers in a positional numeral system, a base outside 2 to
+// 36 renders them in decimal.
+type Base int
+
+// NewBase returns the renderer for base.
+func NewBase(base int) (Base, error) {
+	if !isValidBase(base) {
+		return 0, ErrInvalidBase
+	}
+	return Base(base), nil
+}
+
+// radix returns the base numbers are written in.
+func (b Base) radix() int {
+	if !isValidBase(int(b)) {
+		return 10
+	}
+	return int(b)
+}
+
+func isValidBase(base int) bool {
+	return base >= 2 && base <= 36
+}
+
+// Render writes number in base b.
+func (b Base) Render(number int) string {
+	return strconv.FormatInt(int64(number), b.radix())
+}
+
+// positional renderers
+const (
+	Binary      Base = 2
+	Octal       Base = 8
+	Decimal     Base = 10
+	Hexadecimal Base = 16
+)
+
+type roman struct{}
+
+// Roman renders numbers from 1 to 3999 as Roman numerals and any other
+// number in decimal.
+var Roman Renderer = roman{}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+func (roman) Render(number int) string {
+	if number < 1 || number > 3999 {
+		return strconv.Itoa(number)
+	}
+	var sb strings.Builder
+	for _, rn := range romanNumerals {
+		for number >= rn.value {
+			sb.WriteString(rn.symbol)
+			number -= rn.value
+		}
+	}
+	return sb.String()
+}
+
+type english struct{}
+
+// English spells numbers out in English words.
+var English Renderer = english{}
+
+var (
+	englishSmall = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	englishTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	englishScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+func spellHundreds(number int) string {
+	var words []string
+	if number >= 100 {
+		words = append(words, englishSmall[number/100], "hundred")
+		number %= 100
+		if number == 0 {
+			return strings.Join(words, " ")
+		}
+	}
+	switch {
+	case number < 20:
+		words = append(words, englishSmall[number])
+	case number%10 == 0:
+		words = append(words, englishTens[number/10])
+	default:
+		words = append(words, englishTens[number/10]+"-"+englishSmall[number%10])
+	}
+	return strings.Join(words, " ")
+}
+
+func (english) Render(number int) string {
+	if number == 0 {
+		return englishSmall[0]
+	}
+
+	n := uint64(number)
+	prefix := ""
+	if number < 0 {
+		n = uint64(-(number + 1)) + 1
+		prefix = "minus "
+	}
+
+	var groups []string
+	for scale := 0; n > 0; scale++ {
+		if group := int(n % 1000); group != 0 {
+			words := spellHundreds(group)
+			if englishScales[scale] != "" {
+				words += " " + englishScales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		n /= 1000
+	}
+	return prefix + strings.Join(groups, " ")
+}
+
+// RendererByName returns the renderer called binary, octal, decimal, hex,
+// roman or english.
+func RendererByName(name string) (Renderer, error) {
+	switch name {
+	case "binary":
+		return Binary, nil
+	case "octal":
+		return Octal, nil
+	case "decimal":
+		return Decimal, nil
+	case "hex":
+		return Hexadecimal, nil
+	case "roman":
+		return Roman, nil
+	case "english":
+		return English, nil
+	default:
+		return nil, ErrUnknownRenderer
+	}
+}
+
+func withBase(p Predicate, base int) Predicate {
+	switch p := p.(type) {
+	case contains:
+		return contains{p.subNumber, base}
+	case endsWith:
+		return endsWith{p.suffix, base}
+	case all:
+		result := make(all, len(p))
+		for i, sub := range p {
+			result[i] = withBase(sub, base)
+		}
+		return result
+	case anyOf:
+		result := make(anyOf, len(p))
+		for i, sub := range p {
+			result[i] = withBase(sub, base)
+		}
+		return result
+	default:
+		return p
+	}
+}
@@ -0,0 +1,115 @@
+package fizzbuzz
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	renderTests := []struct {
+		renderer Renderer
+		number   int
+		want     string
+	}{
+		{Binary, 5, "101"},
+		{Hexadecimal, 255, "ff"},
+		{Roman, 1994, "MCMXCIV"},
+		{Roman, 4000, "4000"},
+		{English, 0, "zero"},
+		{English, 100, "one hundred"},
+		{English, 1000001, "one million one"},
+		{English, -42, "minus forty-two"},
+		{Base(1), 12, "12"},
+		{Base(37), 12, "12"},
+		{English, 9223372036854775807, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+	}
+
+	for _, tt := range renderTests {
+		assertString(t, tt.renderer.Render(tt.number), tt.want)
+	}
+}
+
+func TestNewBase(t *testing.T) {
+	for _, base := range []int{-2, 0, 1, 37} {
+		if _, err := NewBase(base); err != ErrInvalidBase {
+			t.Errorf("%d got %v, want %v", base, err, ErrInvalidBase)
+		}
+	}
+	b, err := NewBase(36)
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+	assertString(t, b.Render(35), "z")
+
+	game := NewGame().AddRule(Contains(1), "one").SetRenderer(Base(1))
+	assertString(t, game.Play(21), "one")
+	assertString(t, game.Play(22), "22")
+	got, err := game.PlayDecimal("123456789012345678901234567890")
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+	assertString(t, got, "one")
+}
+
+func TestInvalidPredicateBase(t *testing.T) {
+	for _, base := range []int{0, 1, 37} {
+		game := NewGame().
+			AddRule(ContainsIn(1, base), "one").
+			AddRule(EndsWithIn(2, base), "two").
+			AddRule(DivisibleBy(3), "fizz").
+			SetPolicy(Concatenate)
+
+		for _, number := range []int{1, 2, 3, 12} {
+			want := Decimal.Render(number)
+			if number%3 == 0 {
+				want = "fizz"
+			}
+			assertString(t, game.Play(number), want)
+			got, err := game.PlayDecimal(Decimal.Render(number))
+			if err != nil {
+				t.Fatalf("got error %v but didn't want one", err)
+			}
+			assertString(t, got, want)
+		}
+
+		counts, err := game.Count(0, 100)
+		if err != nil {
+			t.Fatalf("base %d got error %v but didn't want one", base, err)
+		}
+		assertCounts(t, counts, bruteForceCount(game, 0, 100))
+	}
+}
+
+func TestBase(t *testing.T) {
+	game := NewGame().
+		AddRule(ContainsIn(3, 2), "ones").
+		AddRule(DivisibleBy(5), "buzz").
+		SetRenderer(Binary).
+		AddRule(EndsWith(2), "even")
+
+	gameTests := []struct {
+		number int
+		result string
+	}{
+		{1, "1"},
+		{3, "ones"},
+		{5, "buzz"},
+		{6, "ones"},
+		{18, "even"},
+		{9, "1001"},
+	}
+
+	for _, tt := range gameTests {
+		got := game.Play(tt.number)
+		assertString(t, got, tt.result)
+
+		want, err := game.PlayDecimal(Decimal.Render(tt.number))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		assertString(t, got, want)
+	}
+
+	counts, err := game.Count(0, 1000)
+	if err != nil {
+		t.Fatalf("got error %v but didn't want one", err)
+	}
+	assertCounts(t, counts, bruteForceCount(game, 0, 1000))
+}
@@ -0,0 +1,307 @@
+package fizzbuzz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Predicate decides whether a rule applies to a number.
+type Predicate interface {
+	Match(number int) bool
+	String() string
+}
+
+type divisibleBy struct {
+	divisor int
+}
+
+// DivisibleBy matches numbers that are divisible by divisor, a zero
+// divisor never matches.
+func DivisibleBy(divisor int) Predicate {
+	return divisibleBy{divisor}
+}
+
+func (d divisibleBy) Match(number int) bool {
+	return isDivisibleBy(number, d.divisor)
+}
+
+func (d divisibleBy) String() string {
+	return fmt.Sprintf("divisible by %d", d.divisor)
+}
+
+func baseSuffix(base int) string {
+	if base == 10 {
+		return ""
+	}
+	return fmt.Sprintf(" in base %d", base)
+}
+
+type contains struct {
+	subNumber int
+	base      int
+}
+
+// Contains matches numbers whose decimal form contains subNumber.
+func Contains(subNumber int) Predicate {
+	return contains{subNumber, 10}
+}
+
+// ContainsIn matches numbers whose form in base contains the form of
+// subNumber in base, it never matches for a base outside 2 to 36.
+func ContainsIn(subNumber, base int) Predicate {
+	return contains{subNumber, base}
+}
+
+func (c contains) Match(number int) bool {
+	if c.base == 10 {
+		return isContains(number, c.subNumber)
+	}
+	return isValidBase(c.base) && isContainsIn(number, c.subNumber, c.base)
+}
+
+func (c contains) String() string {
+	return fmt.Sprintf("contains %d%s", c.subNumber, baseSuffix(c.base))
+}
+
+type endsWith struct {
+	suffix int
+	base   int
+}
+
+// EndsWith matches numbers whose decimal form ends with suffix.
+func EndsWith(suffix int) Predicate {
+	return endsWith{suffix, 10}
+}
+
+// EndsWithIn matches numbers whose form in base ends with the form of
+// suffix in base, it never matches for a base outside 2 to 36.
+func EndsWithIn(suffix, base int) Predicate {
+	return endsWith{suffix, base}
+}
+
+func (e endsWith) Match(number int) bool {
+	if e.base == 10 {
+		return isEndsWith(number, e.suffix)
+	}
+	return isValidBase(e.base) && isEndsWithIn(number, e.suffix, e.base)
+}
+
+func (e endsWith) String() string {
+	return fmt.Sprintf("ends with %d%s", e.suffix, baseSuffix(e.base))
+}
+
+type funcPredicate struct {
+	name  string
+	match func(int) bool
+}
+
+// Func matches numbers for which match returns true, name describes it.
+func Func(name string, match func(int) bool) Predicate {
+	return funcPredicate{name, match}
+}
+
+func (f funcPredicate) Match(number int) bool {
+	return f.match(number)
+}
+
+func (f funcPredicate) String() string {
+	return f.name
+}
+
+type all []Predicate
+
+// All matches numbers that match every one of predicates.
+func All(predicates ...Predicate) Predicate {
+	return all(predicates)
+}
+
+func (a all) Match(number int) bool {
+	for _, p := range a {
+		if !p.Match(number) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a all) String() string {
+	result := make([]string, 0, len(a))
+	for _, p := range a {
+		result = append(result, p.String())
+	}
+	return strings.Join(result, " and ")
+}
+
+type anyOf []Predicate
+
+// Any matches numbers that match at least one of predicates.
+func Any(predicates ...Predicate) Predicate {
+	return anyOf(predicates)
+}
+
+func (a anyOf) Match(number int) bool {
+	for _, p := range a {
+		if p.Match(number) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a anyOf) String() string {
+	result := make([]string, 0, len(a))
+	for _, p := range a {
+		result = append(result, p.String())
+	}
+	return strings.Join(result, " or ")
+}
+
+type digitSum struct {
+	sum int
+}
+
+// DigitSum matches numbers whose decimal digits add up to sum.
+func DigitSum(sum int) Predicate {
+	return digitSum{sum}
+}
+
+func (d digitSum) Match(number int) bool {
+	return isDigitSum(number, d.sum)
+}
+
+func (d digitSum) String() string {
+	return fmt.Sprintf("digit sum is %d", d.sum)
+}
+
+// Rule says a number is Word when Predicate matches, Priority is only
+// used by the Priority policy.
+type Rule struct {
+	Predicate Predicate
+	Word      string
+	Priority  int
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s -> %s", r.Predicate, r.Word)
+}
+
+// Game evaluates numbers against its rules and lets its policy decide
+// how matching rules combine. Words are translated by its vocabulary and
+// numbers no rule is used for are written by its renderer.
+type Game struct {
+	rules      []Rule
+	policy     Policy
+	vocabulary Vocabulary
+	renderer   Renderer
+	base       int
+}
+
+// NewGame creates a game with rules, the FirstMatch policy and decimal
+// numbers.
+func NewGame(rules ...Rule) *Game {
+	g := new(Game)
+	g.rules = append(g.rules, rules...)
+	g.policy = FirstMatch
+	g.renderer = Decimal
+	g.base = 10
+	return g
+}
+
+// AddRule registers a rule after the existing ones.
+func (g *Game) AddRule(predicate Predicate, word string) *Game {
+	return g.AddPriorityRule(predicate, word, 0)
+}
+
+// AddPriorityRule registers a rule with a priority for the Priority policy.
+func (g *Game) AddPriorityRule(predicate Predicate, word string, priority int) *Game {
+	if g.base != 10 {
+		predicate = withBase(predicate, g.base)
+	}
+	g.rules = append(g.rules, Rule{predicate, word, priority})
+	return g
+}
+
+// SetVocabulary translates the words of the rules with v.
+func (g *Game) SetVocabulary(v Vocabulary) *Game {
+	g.vocabulary = v
+	return g
+}
+
+// SetRenderer writes the numbers no rule is used for with r. When r is a
+// Base the containment and suffix rules are evaluated in that base too.
+func (g *Game) SetRenderer(r Renderer) *Game {
+	g.renderer = r
+	g.base = 10
+	if b, ok := r.(Base); ok {
+		g.base = b.radix()
+	}
+	for i, rule := range g.rules {
+		g.rules[i].Predicate = withBase(rule.Predicate, g.base)
+	}
+	return g
+}
+
+// SetPolicy changes how matching rules combine.
+func (g *Game) SetPolicy(p Policy) *Game {
+	g.policy = p
+	return g
+}
+
+// Policy returns the game's combination policy.
+func (g *Game) Policy() Policy {
+	return g.policy
+}
+
+// Rules returns a copy of the game's rules.
+func (g *Game) Rules() []Rule {
+	return append([]Rule(nil), g.rules...)
+}
+
+func (g *Game) matched(number int) []Rule {
+	var result []Rule
+	for _, r := range g.rules {
+		if r.Predicate.Match(number) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func (g *Game) joinWords(used []Rule) string {
+	if len(used) == 1 {
+		return g.vocabulary.Translate(used[0].Word)
+	}
+	var sb strings.Builder
+	for _, r := range used {
+		sb.WriteString(g.vocabulary.Translate(r.Word))
+	}
+	return sb.String()
+}
+
+func (g *Game) render(number int) string {
+	if g.renderer == Decimal {
+		return strconv.Itoa(number)
+	}
+	return g.renderer.Render(number)
+}
+
+// Play returns the word the policy builds from the matching rules or the
+// number itself when no rule is used.
+func (g *Game) Play(number int) string {
+	if g.policy == FirstMatch {
+		for _, r := range g.rules {
+			if r.Predicate.Match(number) {
+				return g.vocabulary.Translate(r.Word)
+			}
+		}
+		return g.render(number)
+	}
+
+	matched := g.matched(number)
+	used := g.policy.Combine(matched)
+	if len(used) == 0 {
+		return g.render(number)
+	}
+	return g.joinWords(used)
+}
@@ -0,0 +1,409 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The rule file format describes a game line by line:
+//
+//	# classic fizzbuzz
+//	policy first-match
+//	fizzbuzz: divisible 3 and divisible 5
+//	fizz: divisible 3 or contains 3
+//	"fizz buzz" priority 2: (contains 3 or endswith 9) and digitsum 10
+//
+// A rule line is a word, an optional priority and a condition built from
+// divisible, contains, endswith and digitsum with and, or and parentheses,
+// and binding tighter than or. Words that aren't plain identifiers or that
+// are keywords must be quoted.
+
+// SyntaxError is a problem in a rule file at a line and column.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOL tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenColon
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOL {
+		return "end of line"
+	}
+	return strconv.Quote(t.text)
+}
+
+var ruleKeywords = map[string]bool{
+	"policy": true, "priority": true, "and": true, "or": true,
+	"divisible": true, "contains": true, "endswith": true, "digitsum": true,
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c == '-' || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func tokenize(line string, lineNumber int) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(line); {
+		c := line[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '#':
+			i = len(line)
+			continue
+		case c == ':':
+			tokens = append(tokens, token{tokenColon, ":", start + 1})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLeftParen, "(", start + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRightParen, ")", start + 1})
+			i++
+		case isIdentStart(c):
+			for i < len(line) && isIdentPart(line[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, line[start:i], start + 1})
+		case isDigit(c) || (c == '-' && i+1 < len(line) && isDigit(line[i+1])):
+			i++
+			for i < len(line) && isDigit(line[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, line[start:i], start + 1})
+		case c == '"':
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(line) {
+				return nil, &SyntaxError{lineNumber, start + 1, "unterminated string"}
+			}
+			i++
+			word, err := strconv.Unquote(line[start:i])
+			if err != nil {
+				return nil, &SyntaxError{lineNumber, start + 1, "invalid string " + line[start:i]}
+			}
+			tokens = append(tokens, token{tokenString, word, start + 1})
+		default:
+			return nil, &SyntaxError{lineNumber, start + 1, fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, token{tokenEOL, "", len(line) + 1}), nil
+}
+
+type ruleParser struct {
+	tokens []token
+	pos    int
+	line   int
+}
+
+func (p *ruleParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOL {
+		p.pos++
+	}
+	return t
+}
+
+func (p *ruleParser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{p.line, t.column, fmt.Sprintf(format, args...)}
+}
+
+func (p *ruleParser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s, found %s", what, t)
+	}
+	return t, nil
+}
+
+func (p *ruleParser) number() (int, error) {
+	t, err := p.expect(tokenNumber, "number")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, p.errorf(t, "number %s out of range", t.text)
+	}
+	return n, nil
+}
+
+func (p *ruleParser) or() (Predicate, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{left}
+	for t := p.peek(); t.kind == tokenIdent && t.text == "or"; t = p.peek() {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, right)
+	}
+	if len(predicates) == 1 {
+		return left, nil
+	}
+	return Any(predicates...), nil
+}
+
+func (p *ruleParser) and() (Predicate, error) {
+	left, err := p.condition()
+	if err != nil {
+		return nil, err
+	}
+	predicates := []Predicate{left}
+	for t := p.peek(); t.kind == tokenIdent && t.text == "and"; t = p.peek() {
+		p.next()
+		right, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, right)
+	}
+	if len(predicates) == 1 {
+		return left, nil
+	}
+	return All(predicates...), nil
+}
+
+func (p *ruleParser) condition() (Predicate, error) {
+	t := p.next()
+	if t.kind == tokenLeftParen {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	if t.kind != tokenIdent {
+		return nil, p.errorf(t, "expected condition, found %s", t)
+	}
+	var newPredicate func(int) Predicate
+	switch t.text {
+	case "divisible":
+		newPredicate = DivisibleBy
+	case "contains":
+		newPredicate = Contains
+	case "endswith":
+		newPredicate = EndsWith
+	case "digitsum":
+		newPredicate = DigitSum
+	default:
+		return nil, p.errorf(t, "unknown condition %s", t)
+	}
+
+	operandToken := p.peek()
+	n, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	if t.text == "divisible" && n == 0 {
+		return nil, p.errorf(operandToken, "divisor must not be zero")
+	}
+	return newPredicate(n), nil
+}
+
+func (p *ruleParser) statement(g *Game) error {
+	first := p.next()
+	if first.kind == tokenIdent && first.text == "policy" {
+		t, err := p.expect(tokenIdent, "policy name")
+		if err != nil {
+			return err
+		}
+		policy, err := PolicyByName(t.text)
+		if err != nil {
+			return p.errorf(t, "unknown policy %s", t)
+		}
+		g.SetPolicy(policy)
+		_, err = p.expect(tokenEOL, "end of line")
+		return err
+	}
+
+	if first.kind != tokenString && (first.kind != tokenIdent || ruleKeywords[first.text]) {
+		return p.errorf(first, "expected word or policy, found %s", first)
+	}
+
+	priority := 0
+	if t := p.peek(); t.kind == tokenIdent && t.text == "priority" {
+		p.next()
+		n, err := p.number()
+		if err != nil {
+			return err
+		}
+		priority = n
+	}
+	if _, err := p.expect(tokenColon, "\":\""); err != nil {
+		return err
+	}
+	predicate, err := p.or()
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(tokenEOL, "end of line"); err != nil {
+		return err
+	}
+	g.AddPriorityRule(predicate, first.text, priority)
+	return nil
+}
+
+// ParseRules reads a game in the rule file format.
+func ParseRules(r io.Reader) (*Game, error) {
+	g := NewGame()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		tokens, err := tokenize(scanner.Text(), line)
+		if err != nil {
+			return nil, err
+		}
+		if tokens[0].kind == tokenEOL {
+			continue
+		}
+		p := &ruleParser{tokens: tokens, line: line}
+		if err := p.statement(g); err != nil {
+			return nil, err
+		}
+	}
+	return g, scanner.Err()
+}
+
+// LoadRulesFile reads a game from the rule file at path.
+func LoadRulesFile(path string) (*Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+	defer f.Close()
+	return ParseRules(f)
+}
+
+func formatWord(word string) string {
+	if word == "" || !isIdentStart(word[0]) || ruleKeywords[word] {
+		return strconv.Quote(word)
+	}
+	for i := 0; i < len(word); i++ {
+		if !isIdentPart(word[i]) {
+			return strconv.Quote(word)
+		}
+	}
+	return word
+}
+
+func formatPredicate(p Predicate, nested bool) (string, error) {
+	var parts []string
+	var separator string
+	switch p := p.(type) {
+	case divisibleBy:
+		if p.divisor == 0 {
+			return "", ErrUnsupportedPredicate
+		}
+		return fmt.Sprintf("divisible %d", p.divisor), nil
+	case contains:
+		if p.base != 10 {
+			return "", ErrUnsupportedPredicate
+		}
+		return fmt.Sprintf("contains %d", p.subNumber), nil
+	case endsWith:
+		if p.base != 10 {
+			return "", ErrUnsupportedPredicate
+		}
+		return fmt.Sprintf("endswith %d", p.suffix), nil
+	case digitSum:
+		return fmt.Sprintf("digitsum %d", p.sum), nil
+	case all:
+		separator = " and "
+		for _, sub := range p {
+			text, err := formatPredicate(sub, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, text)
+		}
+	case anyOf:
+		separator = " or "
+		for _, sub := range p {
+			text, err := formatPredicate(sub, true)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, text)
+		}
+	default:
+		return "", ErrUnsupportedPredicate
+	}
+
+	text := strings.Join(parts, separator)
+	if nested && len(parts) > 1 {
+		text = "(" + text + ")"
+	}
+	return text, nil
+}
+
+// FormatRules writes a game in the rule file format, Func predicates,
+// containment in other bases and zero divisors can't be written.
+func FormatRules(g *Game) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "policy %s\n", g.policy.Name())
+	for _, r := range g.rules {
+		condition, err := formatPredicate(r.Predicate, false)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(formatWord(r.Word))
+		if r.Priority != 0 {
+			fmt.Fprintf(&sb, " priority %d", r.Priority)
+		}
+		fmt.Fprintf(&sb, ": %s\n", condition)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,127 @@
+package fizzbuzz
+
+import (
+	"strings"
+	"testing"
+)
+
+const classicRules = `# classic fizzbuzz
+policy first-match
+fizzbuzz: divisible 3 and divisible 5
+fizzbuzz: contains 3 and contains 5
+fizz: divisible 3
+buzz: divisible 5
+fizz: contains 3
+buzz: contains 5
+`
+
+func TestParseRules(t *testing.T) {
+	t.Run("classic", func(t *testing.T) {
+		game, err := ParseRules(strings.NewReader(classicRules))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		for number := 1; number <= 1000; number++ {
+			assertString(t, game.Play(number), FizzBuzz(number))
+		}
+	})
+
+	t.Run("variant", func(t *testing.T) {
+		game, err := ParseRules(strings.NewReader(`
+policy concatenate
+fizz: divisible 3 or contains 3
+buzz: divisible 5   # no containment
+whizz: divisible 7
+bang: digitsum 10
+"big one" priority 3: (endswith 99 or contains 100) and divisible 11
+`))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+
+		gameTests := []struct {
+			number int
+			result string
+		}{
+			{13, "fizz"},
+			{19, "bang"},
+			{35, "fizzbuzzwhizz"},
+			{1100, "buzzbig one"},
+			{4, "4"},
+		}
+		for _, tt := range gameTests {
+			assertString(t, game.Play(tt.number), tt.result)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		errorTests := []struct {
+			text string
+			want string
+		}{
+			{"policy last-match", `line 1:8: unknown policy "last-match"`},
+			{"fizz divisible 3", `line 1:6: expected ":", found "divisible"`},
+			{"\nfizz: divisible", "line 2:16: expected number, found end of line"},
+			{"fizz: divisible 3 and", "line 1:22: expected condition, found end of line"},
+			{"fizz: (divisible 3", "line 1:19: expected \")\", found end of line"},
+			{"fizz: prime 3", `line 1:7: unknown condition "prime"`},
+			{"fizz: divisible 0", "line 1:17: divisor must not be zero"},
+			{`"fizz: divisible 3`, "line 1:1: unterminated string"},
+			{"fizz: divisible 3 !", "line 1:19: unexpected character '!'"},
+			{"or: divisible 3", `line 1:1: expected word or policy, found "or"`},
+		}
+
+		for _, tt := range errorTests {
+			_, err := ParseRules(strings.NewReader(tt.text))
+			if _, ok := err.(*SyntaxError); !ok {
+				t.Fatalf("%q got %v, want a syntax error", tt.text, err)
+			}
+			assertString(t, err.Error(), tt.want)
+		}
+	})
+}
+
+func TestFormatRules(t *testing.T) {
+	t.Run("default game", func(t *testing.T) {
+		got, err := FormatRules(NewGame(DefaultRules()...))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		assertString(t, got, strings.TrimPrefix(classicRules, "# classic fizzbuzz\n"))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		text := `policy priority
+fizz: divisible 3 or contains 3
+"fizz buzz" priority 2: (contains 3 or endswith 9) and digitsum 10
+"and" priority -1: ((divisible 2 and divisible 7) or contains 0) and divisible 4
+`
+		game, err := ParseRules(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		got, err := FormatRules(game)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		assertString(t, got, text)
+	})
+
+	t.Run("zero divisor", func(t *testing.T) {
+		game := NewGame().AddRule(Any(DivisibleBy(0), Contains(3)), "fizz")
+		text, err := FormatRules(game)
+		if err != ErrUnsupportedPredicate {
+			t.Errorf("got %q %v, want %v", text, err, ErrUnsupportedPredicate)
+		}
+		if _, err := ParseRules(strings.NewReader("fizz: divisible 0\n")); err == nil {
+			t.Errorf("got no error parsing a zero divisor")
+		}
+	})
+
+	t.Run("func predicate", func(t *testing.T) {
+		_, err := FormatRules(NewGame().AddRule(Func("even", func(n int) bool { return n%2 == 0 }), "even"))
+		if err != ErrUnsupportedPredicate {
+			t.Errorf("got %v, want %v", err, ErrUnsupportedPredicate)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package fizzbuzz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// MaxPageSize is the largest number of results one request returns.
+const MaxPageSize = 10000
+
+// MaxRequestSize is the largest request body the server reads.
+const MaxRequestSize = 1 << 20
+
+// ErrNoNumbers is returned when an evaluate request has neither numbers
+// nor a range.
+var ErrNoNumbers = errors.New("request has neither numbers nor from and to")
+
+const (
+	jsonContentType      = "application/json"
+	jsonLinesContentType = "application/x-ndjson"
+	textContentType      = "text/plain; charset=utf-8"
+)
+
+// NextFromHeader tells where the next page of a range starts.
+const NextFromHeader = "X-Next-From"
+
+// Server answers fizzbuzz queries over HTTP.
+type Server struct {
+	game *Game
+	http.Handler
+}
+
+// NewServer creates a server answering with game.
+func NewServer(game *Game) *Server {
+	s := new(Server)
+	s.game = game
+
+	router := http.NewServeMux()
+	router.HandleFunc("/fizzbuzz", s.handleRange)
+	router.HandleFunc("/fizzbuzz/evaluate", s.handleEvaluate)
+	router.HandleFunc("/fizzbuzz/", s.handleNumber)
+
+	s.Handler = router
+
+	return s
+}
+
+func (s *Server) handleNumber(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	number := r.URL.Path[len("/fizzbuzz/"):]
+	word, err := s.game.PlayDecimal(number)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("content-type", textContentType)
+	fmt.Fprint(w, word)
+}
+
+// page is the part of [from, to] a request answers.
+type page struct {
+	from, to int
+	nextFrom int
+	hasNext  bool
+}
+
+func newPage(from, to, limit int) (page, error) {
+	if from > to {
+		return page{}, ErrInvalidRange
+	}
+	if limit < 1 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	p := page{from: from, to: to}
+	if uint64(to)-uint64(from) >= uint64(limit) {
+		p.to = from + limit - 1
+		p.nextFrom = p.to + 1
+		p.hasNext = true
+	}
+	return p, nil
+}
+
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", name, err)
+	}
+	return n, nil
+}
+
+func (s *Server) handleRange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, err := queryInt(r, "from", 1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := queryInt(r, "to", 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", MaxPageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, err := newPage(from, to, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := FormatText
+	contentType := textContentType
+	if r.URL.Query().Get("format") == "json" {
+		format = FormatJSON
+		contentType = jsonLinesContentType
+	}
+
+	w.Header().Set("content-type", contentType)
+	if p.hasNext {
+		w.Header().Set(NextFromHeader, strconv.Itoa(p.nextFrom))
+	}
+	if err := s.game.WriteRange(w, p.from, p.to, 1, format); err != nil {
+		// the status is sent already, abort so the client doesn't take a
+		// short page for a whole one
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// EvaluateRequest asks to evaluate numbers with the game of its spec,
+// either the listed Numbers or the range [From, To].
+type EvaluateRequest struct {
+	GameSpec
+	Numbers []int `json:"numbers,omitempty"`
+	From    *int  `json:"from,omitempty"`
+	To      *int  `json:"to,omitempty"`
+}
+
+func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("request larger than %d bytes", MaxRequestSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	var req EvaluateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("problem parsing request, %v", err), http.StatusBadRequest)
+		return
+	}
+	game, err := req.GameSpec.Game()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	numbers := req.Numbers
+	if len(numbers) == 0 {
+		if req.From == nil || req.To == nil {
+			http.Error(w, ErrNoNumbers.Error(), http.StatusBadRequest)
+			return
+		}
+		p, err := newPage(*req.From, *req.To, MaxPageSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.hasNext {
+			w.Header().Set(NextFromHeader, strconv.Itoa(p.nextFrom))
+		}
+		for n := p.from; ; n++ {
+			numbers = append(numbers, n)
+			if n == p.to {
+				break
+			}
+		}
+	}
+	if len(numbers) > MaxPageSize {
+		http.Error(w, fmt.Sprintf("at most %d numbers per request", MaxPageSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	results := make([]Result, 0, len(numbers))
+	for _, n := range numbers {
+		results = append(results, Result{n, game.Play(n)})
+	}
+	w.Header().Set("content-type", jsonContentType)
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,213 @@
+package fizzbuzz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGETNumber(t *testing.T) {
+	server := NewServer(NewGame(DefaultRules()...))
+
+	numberTests := []struct {
+		number string
+		status int
+		body   string
+	}{
+		{"15", http.StatusOK, "fizzbuzz"},
+		{"7", http.StatusOK, "7"},
+		{"100000000000000000000000000000001", http.StatusOK, "100000000000000000000000000000001"},
+		{"abc", http.StatusBadRequest, ErrInvalidNumber.Error() + "\n"},
+	}
+
+	for _, tt := range numberTests {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz/"+tt.number, nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, tt.status)
+		assertString(t, response.Body.String(), tt.body)
+	}
+}
+
+func TestGETRange(t *testing.T) {
+	server := NewServer(NewGame(DefaultRules()...))
+
+	t.Run("text", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz?from=3&to=5", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertString(t, response.Body.String(), "fizz\n4\nbuzz\n")
+		assertString(t, response.Header().Get(NextFromHeader), "")
+	})
+
+	t.Run("json lines with pagination", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz?from=14&to=1000&limit=2&format=json", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertString(t, response.Header().Get("content-type"), jsonLinesContentType)
+		assertString(t, response.Body.String(), "{\"number\":14,\"word\":\"14\"}\n{\"number\":15,\"word\":\"fizzbuzz\"}\n")
+		assertString(t, response.Header().Get(NextFromHeader), "16")
+	})
+
+	t.Run("limited page", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz?from=1&to=9223372036854775807", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		lines := strings.Count(response.Body.String(), "\n")
+		if lines != MaxPageSize {
+			t.Errorf("got %d lines, want %d", lines, MaxPageSize)
+		}
+	})
+
+	t.Run("failed write aborts", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz?from=1&to=100", nil)
+		response := failingResponseWriter{httptest.NewRecorder()}
+
+		defer func() {
+			if got := recover(); got != http.ErrAbortHandler {
+				t.Errorf("got panic %v, want %v", got, http.ErrAbortHandler)
+			}
+		}()
+		server.ServeHTTP(response, request)
+	})
+
+	t.Run("bad range", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz?from=5&to=1", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
+
+func TestPOSTEvaluate(t *testing.T) {
+	server := NewServer(NewGame(DefaultRules()...))
+
+	t.Run("rule set", func(t *testing.T) {
+		body := `{
+			"policy": "concatenate",
+			"rules": [
+				{"word": "fizz", "divisible_by": [3]},
+				{"word": "buzz", "divisible_by": [5]},
+				{"word": "whizz", "divisible_by": [7]}
+			],
+			"numbers": [1, 15, 105]
+		}`
+		request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		var got []Result
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to decode %q, %v", response.Body, err)
+		}
+		want := []Result{{1, "1"}, {15, "fizzbuzz"}, {105, "fizzbuzzwhizz"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		body := `{"rules": [{"word": "fizz", "divisible_by": [3]}], "from": 1, "to": 20000}`
+		request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertString(t, response.Header().Get(NextFromHeader), "10001")
+	})
+
+	t.Run("range at zero", func(t *testing.T) {
+		body := `{"rules": [{"word": "fizz", "divisible_by": [3]}], "from": 0, "to": 0}`
+		request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertString(t, response.Body.String(), `[{"number":0,"word":"fizz"}]`+"\n")
+	})
+
+	t.Run("bad rule set", func(t *testing.T) {
+		for _, body := range []string{
+			`{"policy": "last-match", "rules": [], "numbers": [1]}`,
+			`{"rules": [{"word": "fizz"}], "numbers": [1]}`,
+			`{"rules": [{"word": "fizz", "divisible_by": [0]}], "numbers": [1]}`,
+			`{"rules": [{"word": "fizz", "divisible_by": [3]}]}`,
+			`{"rules": [{"word": "fizz", "divisible_by": [3]}], "numbers": [], "from": 1}`,
+			`not json`,
+		} {
+			request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("too many numbers", func(t *testing.T) {
+		numbers := strings.Repeat("1,", MaxPageSize)
+		body := `{"rules": [{"word": "fizz", "divisible_by": [3]}], "numbers": [` + numbers + `1]}`
+		request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		body := `{"numbers": [` + strings.Repeat(" ", MaxRequestSize) + `1]}`
+		request, _ := http.NewRequest(http.MethodPost, "/fizzbuzz/evaluate", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/fizzbuzz/evaluate", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusMethodNotAllowed)
+	})
+}
+
+// failingResponseWriter fails every write of the body.
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func assertStatus(t *testing.T, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got status %d, want %d", got, want)
+	}
+}
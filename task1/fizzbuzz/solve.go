@@ -0,0 +1,267 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrTooManyWords is returned when an observed sequence uses more words
+// than the solver searches for.
+var ErrTooManyWords = errors.New("too many distinct words to solve")
+
+// ErrInconsistentSequence is returned when a number in an observed sequence
+// isn't the one at its position.
+var ErrInconsistentSequence = errors.New("number doesn't match its position in the sequence")
+
+const maxSolveWords = 3
+
+// WordCondition says when a variant gives a number Word: when the number
+// is divisible by Divisor or contains Digit. Divisor 0 and Digit -1 mean
+// the condition isn't used.
+type WordCondition struct {
+	Word    string
+	Divisor int
+	Digit   int
+}
+
+func (wc WordCondition) String() string {
+	var conditions []string
+	if wc.Divisor != 0 {
+		conditions = append(conditions, DivisibleBy(wc.Divisor).String())
+	}
+	if wc.Digit >= 0 {
+		conditions = append(conditions, Contains(wc.Digit).String())
+	}
+	return fmt.Sprintf("%s when %s", wc.Word, strings.Join(conditions, " or "))
+}
+
+// Variant is a rule set the solver considers, its words are joined in
+// order when the policy concatenates them.
+type Variant struct {
+	Words  []WordCondition
+	Policy Policy
+}
+
+func (v Variant) String() string {
+	result := make([]string, 0, len(v.Words))
+	for _, wc := range v.Words {
+		result = append(result, wc.String())
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(result, ", "), v.Policy.Name())
+}
+
+// Game builds the variant's game. With the FirstMatch policy it is laid out
+// like the FizzBuzz game: all divisors together, then all digits together,
+// then every divisor and every digit alone. With any other policy the
+// divisibility rules come before the containment rules.
+func (v Variant) Game() *Game {
+	g := NewGame().SetPolicy(v.Policy)
+	var divisors, digits []Predicate
+	var divisorWords, digitWords []string
+	for _, wc := range v.Words {
+		if wc.Divisor != 0 {
+			divisors = append(divisors, DivisibleBy(wc.Divisor))
+			divisorWords = append(divisorWords, wc.Word)
+		}
+		if wc.Digit >= 0 {
+			digits = append(digits, Contains(wc.Digit))
+			digitWords = append(digitWords, wc.Word)
+		}
+	}
+
+	if v.Policy == FirstMatch {
+		if len(divisors) > 1 {
+			g.AddRule(All(divisors...), strings.Join(divisorWords, ""))
+		}
+		if len(digits) > 1 {
+			g.AddRule(All(digits...), strings.Join(digitWords, ""))
+		}
+	}
+	for i, p := range divisors {
+		g.AddRule(p, divisorWords[i])
+	}
+	for i, p := range digits {
+		g.AddRule(p, digitWords[i])
+	}
+	return g
+}
+
+var solvePolicies = []Policy{FirstMatch, Concatenate, DivisibilityFirst}
+
+// Solve searches the variants giving exactly observed for the numbers
+// start, start+1, ... with divisors up to maxDivisor and single digits.
+func Solve(observed []string, start, maxDivisor int) ([]Variant, error) {
+	words, numbers, ok := splitObserved(observed, start)
+	if !ok {
+		return nil, ErrInconsistentSequence
+	}
+	if len(words) > maxSolveWords {
+		return nil, ErrTooManyWords
+	}
+
+	candidates := make([][]WordCondition, len(words))
+	for i, word := range words {
+		candidates[i] = wordCandidates(word, numbers, maxDivisor)
+	}
+
+	var result []Variant
+	seen := make(map[string]bool)
+	permute(len(words), func(order []int) {
+		chosen := make([]WordCondition, len(order))
+		var search func(k int)
+		search = func(k int) {
+			if k == len(order) {
+				for _, p := range solvePolicies {
+					v := Variant{append([]WordCondition(nil), chosen...), p}
+					if !isConsistent(v.Game(), observed, start) {
+						continue
+					}
+					key := v.String()
+					if !seen[key] {
+						seen[key] = true
+						result = append(result, v)
+					}
+				}
+				return
+			}
+			for _, wc := range candidates[order[k]] {
+				chosen[k] = wc
+				search(k + 1)
+			}
+		}
+		search(0)
+	})
+	return result, nil
+}
+
+// splitObserved returns the atomic words of observed, the ones that aren't
+// joined from other observed words, and the numbers shown as themselves.
+func splitObserved(observed []string, start int) (words []string, numbers []int, ok bool) {
+	var tokens []string
+	seen := make(map[string]bool)
+	for i, token := range observed {
+		if n, err := strconv.Atoi(token); err == nil {
+			if n != start+i {
+				return nil, nil, false
+			}
+			numbers = append(numbers, n)
+			continue
+		}
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, token := range tokens {
+		if !isJoined(token, tokens, 0) {
+			words = append(words, token)
+		}
+	}
+	return words, numbers, true
+}
+
+func isJoined(token string, tokens []string, parts int) bool {
+	if token == "" {
+		return parts > 1
+	}
+	for _, t := range tokens {
+		if t != "" && strings.HasPrefix(token, t) && (parts > 0 || t != token) {
+			if isJoined(token[len(t):], tokens, parts+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wordCandidates returns the conditions for word that don't match any
+// number shown as itself, since every policy uses a matching rule.
+func wordCandidates(word string, numbers []int, maxDivisor int) []WordCondition {
+	var result []WordCondition
+	for divisor := 0; divisor <= maxDivisor; divisor++ {
+		if divisor == 1 {
+			continue
+		}
+		for digit := -1; digit <= 9; digit++ {
+			if divisor == 0 && digit == -1 {
+				continue
+			}
+			wc := WordCondition{word, divisor, digit}
+			if !matchesAny(wc, numbers) {
+				result = append(result, wc)
+			}
+		}
+	}
+	return result
+}
+
+func matchesAny(wc WordCondition, numbers []int) bool {
+	for _, n := range numbers {
+		if wc.Divisor != 0 && isDivisibleBy(n, wc.Divisor) {
+			return true
+		}
+		if wc.Digit >= 0 && isContains(n, wc.Digit) {
+			return true
+		}
+	}
+	return false
+}
+
+func isConsistent(g *Game, observed []string, start int) bool {
+	for i, token := range observed {
+		if g.Play(start+i) != token {
+			return false
+		}
+	}
+	return true
+}
+
+// permute calls f with every permutation of 0..n-1.
+func permute(n int, f func(order []int)) {
+	order := make([]int, n)
+	used := make([]bool, n)
+	var walk func(k int)
+	walk = func(k int) {
+		if k == n {
+			f(order)
+			return
+		}
+		for i := 0; i < n; i++ {
+			if !used[i] {
+				used[i] = true
+				order[k] = i
+				walk(k + 1)
+				used[i] = false
+			}
+		}
+	}
+	walk(0)
+}
+
+// FindOffsets returns every start in [from, to] where the game gives
+// fragment for start, start+1, ...
+func (g *Game) FindOffsets(fragment []string, from, to int) []int {
+	for i, token := range fragment {
+		if n, err := strconv.Atoi(token); err == nil {
+			start := n - i
+			if start >= from && start <= to && isConsistent(g, fragment, start) {
+				return []int{start}
+			}
+			return nil
+		}
+	}
+
+	var result []int
+	for start := from; start <= to; start++ {
+		if isConsistent(g, fragment, start) {
+			result = append(result, start)
+		}
+		if start == to {
+			break
+		}
+	}
+	return result
+}
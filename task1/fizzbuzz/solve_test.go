@@ -0,0 +1,110 @@
+package fizzbuzz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	t.Run("classic fizzbuzz", func(t *testing.T) {
+		var observed []string
+		for number := 1; number <= 35; number++ {
+			observed = append(observed, FizzBuzz(number))
+		}
+
+		got, err := Solve(observed, 1, 35)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		want := []Variant{{
+			[]WordCondition{{"fizz", 3, 3}, {"buzz", 5, 5}},
+			FirstMatch,
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("every variant is consistent", func(t *testing.T) {
+		game := NewGame().
+			AddRule(DivisibleBy(3), "fizz").
+			AddRule(DivisibleBy(5), "buzz").
+			SetPolicy(Concatenate)
+		var observed []string
+		for number := 10; number <= 30; number++ {
+			observed = append(observed, game.Play(number))
+		}
+
+		got, err := Solve(observed, 10, 20)
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		for _, v := range got {
+			if !isConsistent(v.Game(), observed, 10) {
+				t.Errorf("%v isn't consistent with %v", v, observed)
+			}
+		}
+
+		variant := func(fizzDigit, buzzDivisor, buzzDigit int, p Policy) Variant {
+			return Variant{[]WordCondition{{"fizz", 3, fizzDigit}, {"buzz", buzzDivisor, buzzDigit}}, p}
+		}
+		want := []Variant{
+			variant(-1, 5, -1, FirstMatch),
+			variant(-1, 5, -1, Concatenate),
+			variant(-1, 5, -1, DivisibilityFirst),
+			variant(-1, 5, 0, FirstMatch),
+			variant(-1, 5, 0, DivisibilityFirst),
+			variant(-1, 5, 5, FirstMatch),
+			variant(-1, 5, 5, DivisibilityFirst),
+			variant(-1, 10, 5, Concatenate),
+			variant(0, 5, -1, FirstMatch),
+			variant(0, 5, -1, DivisibilityFirst),
+			variant(0, 5, 0, DivisibilityFirst),
+			variant(0, 5, 5, FirstMatch),
+			variant(0, 5, 5, DivisibilityFirst),
+			variant(5, 5, -1, FirstMatch),
+			variant(5, 5, -1, DivisibilityFirst),
+			variant(5, 5, 0, FirstMatch),
+			variant(5, 5, 0, DivisibilityFirst),
+			variant(5, 5, 5, DivisibilityFirst),
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("wrong number", func(t *testing.T) {
+		got, err := Solve([]string{"1", "3"}, 1, 10)
+		if err != ErrInconsistentSequence || got != nil {
+			t.Errorf("got %v %v, want %v", got, err, ErrInconsistentSequence)
+		}
+	})
+
+	t.Run("too many words", func(t *testing.T) {
+		_, err := Solve([]string{"a", "b", "c", "d"}, 1, 10)
+		if err != ErrTooManyWords {
+			t.Errorf("got %v, want %v", err, ErrTooManyWords)
+		}
+	})
+}
+
+func TestFindOffsets(t *testing.T) {
+	game := NewGame(DefaultRules()...)
+
+	offsetTests := []struct {
+		fragment []string
+		want     []int
+	}{
+		{[]string{"fizz", "14", "fizzbuzz"}, []int{13}},
+		{[]string{"fizz", "15"}, nil},
+		{[]string{"fizzbuzz", "fizz"}, []int{30, 35, 53}},
+		{[]string{"buzz", "fizz", "buzz"}, []int{50, 56}},
+	}
+
+	for _, tt := range offsetTests {
+		got := game.FindOffsets(tt.fragment, 1, 60)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v got %v, want %v", tt.fragment, got, tt.want)
+		}
+	}
+}
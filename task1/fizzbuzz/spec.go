@@ -0,0 +1,70 @@
+package fizzbuzz
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyRule is returned when a rule spec has no condition.
+var ErrEmptyRule = errors.New("rule has no condition")
+
+// GameSpec is the JSON form of a game.
+type GameSpec struct {
+	Policy string     `json:"policy,omitempty"`
+	Rules  []RuleSpec `json:"rules"`
+}
+
+// RuleSpec is the JSON form of a rule, all of its conditions must match.
+type RuleSpec struct {
+	Word        string `json:"word"`
+	Priority    int    `json:"priority,omitempty"`
+	DivisibleBy []int  `json:"divisible_by,omitempty"`
+	Contains    []int  `json:"contains,omitempty"`
+	EndsWith    []int  `json:"ends_with,omitempty"`
+}
+
+func (rs RuleSpec) predicate() (Predicate, error) {
+	var predicates []Predicate
+	for _, d := range rs.DivisibleBy {
+		if d == 0 {
+			return nil, fmt.Errorf("rule %q: divisor must not be zero", rs.Word)
+		}
+		predicates = append(predicates, DivisibleBy(d))
+	}
+	for _, c := range rs.Contains {
+		predicates = append(predicates, Contains(c))
+	}
+	for _, e := range rs.EndsWith {
+		predicates = append(predicates, EndsWith(e))
+	}
+
+	switch len(predicates) {
+	case 0:
+		return nil, ErrEmptyRule
+	case 1:
+		return predicates[0], nil
+	default:
+		return All(predicates...), nil
+	}
+}
+
+// Game builds the game described by the spec, the policy defaults to
+// first-match.
+func (s GameSpec) Game() (*Game, error) {
+	g := NewGame()
+	if s.Policy != "" {
+		p, err := PolicyByName(s.Policy)
+		if err != nil {
+			return nil, err
+		}
+		g.SetPolicy(p)
+	}
+	for _, rs := range s.Rules {
+		p, err := rs.predicate()
+		if err != nil {
+			return nil, err
+		}
+		g.AddPriorityRule(p, rs.Word, rs.Priority)
+	}
+	return g, nil
+}
@@ -0,0 +1,65 @@
+package fizzbuzz
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Vocabulary translates the words of rules, words it doesn't know are
+// kept as they are.
+type Vocabulary map[string]string
+
+// Translate returns the translation of word.
+func (v Vocabulary) Translate(word string) string {
+	if translation, ok := v[word]; ok {
+		return translation
+	}
+	return word
+}
+
+// LoadVocabulary reads a vocabulary either as a JSON object or as text
+// lines of "word = translation", lines starting with # are comments.
+func LoadVocabulary(r io.Reader) (Vocabulary, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	v := make(Vocabulary)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &v); err != nil {
+			return nil, fmt.Errorf("problem parsing vocabulary, %v", err)
+		}
+		return v, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		pair := strings.SplitN(text, "=", 2)
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" {
+			return nil, fmt.Errorf("problem parsing vocabulary line %d, want word = translation", line)
+		}
+		v[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+	}
+	return v, scanner.Err()
+}
+
+// LoadVocabularyFile reads a vocabulary from the file at path.
+func LoadVocabularyFile(path string) (Vocabulary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+	defer f.Close()
+	return LoadVocabulary(f)
+}
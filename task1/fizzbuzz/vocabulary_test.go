@@ -0,0 +1,64 @@
+package fizzbuzz
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadVocabulary(t *testing.T) {
+	want := Vocabulary{"fizz": "fizz", "buzz": "summ", "fizzbuzz": "fizz summ"}
+
+	t.Run("text", func(t *testing.T) {
+		got, err := LoadVocabulary(strings.NewReader(`
+# german
+fizz = fizz
+buzz=summ
+fizzbuzz = fizz summ
+`))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		got, err := LoadVocabulary(strings.NewReader(`{"fizz": "fizz", "buzz": "summ", "fizzbuzz": "fizz summ"}`))
+		if err != nil {
+			t.Fatalf("got error %v but didn't want one", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("bad line", func(t *testing.T) {
+		_, err := LoadVocabulary(strings.NewReader("fizz = fizz\nbuzz\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("got %v, want an error on line 2", err)
+		}
+	})
+}
+
+func TestVocabularyAndRenderer(t *testing.T) {
+	game := NewGame(DefaultRules()...).
+		SetVocabulary(Vocabulary{"fizz": "fizz", "buzz": "summ", "fizzbuzz": "fizz summ"}).
+		SetRenderer(English)
+
+	gameTests := []struct {
+		number int
+		result string
+	}{
+		{1, "one"},
+		{5, "summ"},
+		{15, "fizz summ"},
+		{22, "twenty-two"},
+	}
+
+	for _, tt := range gameTests {
+		got := game.Play(tt.number)
+		assertString(t, got, tt.result)
+	}
+}
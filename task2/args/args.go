@@ -2,6 +2,7 @@ package args
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -9,6 +10,37 @@ import (
 var WrongSchemaRuleError = errors.New("can't create schema rule, wrong schema rule string")
 var FlagNotExistError = errors.New("not found such flag, flag not exist")
 var ArgValueError = errors.New("argument value error")
+var UnterminatedQuoteError = errors.New("can't split arguments, unterminated quote")
+var TrailingEscapeError = errors.New("can't split arguments, trailing backslash")
+var MissingValueError = errors.New("missing argument value")
+
+// ParseError locates an argument that can't be parsed: its index, the
+// argument itself, the flag and its type, and a near flag when the flag
+// looks like a typo.
+type ParseError struct {
+	Index      int
+	Token      string
+	Flag       string
+	TypeCode   string
+	Suggestion string
+	Err        error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("argument %d %q: %v", e.Index, e.Token, e.Err)
+	if e.TypeCode != "" {
+		msg += ", want " + e.TypeCode
+	}
+	if e.Suggestion != "" {
+		msg += ", did you mean -" + e.Suggestion + "?"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
 
 type SchemaRule struct {
 	flag        string
@@ -32,7 +64,7 @@ func (sr *SchemaRule) getDefaultValue() string {
 	switch sr.getTypeCode() {
 	case "bool":
 		return "true"
-	case "int":
+	case "int", "count":
 		return "0"
 	default:
 		return ""
@@ -67,6 +99,47 @@ func (s *Schema) getSchemaRule(flag string) (*SchemaRule, error) {
 	return nil, FlagNotExistError
 }
 
+// suggest returns the flag nearest to flag by edit distance, or "" when
+// none is near enough to be a typo.
+func (s *Schema) suggest(flag string) string {
+	best, bestDistance := "", 1+len(flag)/3
+	for other := range s.schemaRules {
+		d := editDistance(flag, other)
+		if d < len(flag) && (d < bestDistance || (d == bestDistance && other < best)) {
+			best, bestDistance = other, d
+		}
+	}
+	return best
+}
+
+// editDistance counts the insertions, deletions and substitutions turning a
+// into b, it is kept apart from args2 like splitArgs.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
 func (s *Schema) count() int {
 	return len(s.schemaRules)
 }
@@ -93,19 +166,109 @@ func newParser(aSchemaString string) *Parser {
 	return parser
 }
 
+// splitArgs splits an argument string like a shell does, honoring single
+// quotes, double quotes and backslash escapes. It mirrors args2's
+// SplitArguments so this kata doesn't depend on its successor.
+func splitArgs(aArgString string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	for i := 0; i < len(aArgString); i++ {
+		c := aArgString[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '\\':
+			if i+1 == len(aArgString) {
+				return nil, TrailingEscapeError
+			}
+			i++
+			arg.WriteByte(aArgString[i])
+			inArg = true
+		case c == '\'':
+			end := strings.IndexByte(aArgString[i+1:], '\'')
+			if end < 0 {
+				return nil, UnterminatedQuoteError
+			}
+			arg.WriteString(aArgString[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(aArgString) && aArgString[i] != '"'; i++ {
+				if aArgString[i] == '\\' && i+1 < len(aArgString) &&
+					(aArgString[i+1] == '"' || aArgString[i+1] == '\\') {
+					i++
+				}
+				arg.WriteByte(aArgString[i])
+			}
+			if i == len(aArgString) {
+				return nil, UnterminatedQuoteError
+			}
+			inArg = true
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
 func (p *Parser) parse(aArgString string) error {
-	args := strings.Split(aArgString, " ")
+	args, err := splitArgs(aArgString)
+	if err != nil {
+		return err
+	}
+	return p.parseArgs(args)
+}
+
+// parseArgs parses flags given as -p 8080, --p 8080 or -p=8080. A bool flag
+// takes the next argument when it is true, false, yes, no, 1 or 0 and
+// --no-l sets it false. A count flag counts its occurrences, -vvv is 3.
+// Values of an earlier parse are dropped.
+func (p *Parser) parseArgs(args []string) error {
+	p.argPairs = make(map[string]string, 0)
 	for i := 0; i < len(args); {
-		flag := args[i][1:]
+		flag := strings.TrimLeft(args[i], "-")
+		value, hasValue := "", false
+		if eq := strings.Index(flag, "="); eq >= 0 {
+			flag, value, hasValue = flag[:eq], flag[eq+1:], true
+		}
+
 		sr, err := p.schema.getSchemaRule(flag)
 		if err != nil {
-			return FlagNotExistError
+			if p.parseSwitch(flag) {
+				i++
+				continue
+			}
+			return &ParseError{Index: i, Token: args[i], Flag: flag, Suggestion: p.schema.suggest(flag), Err: FlagNotExistError}
 		}
-		step := 2
-		value := args[i+1]
-		if sr.getTypeCode() == "bool" && value != "true" {
-			step = 1
+
+		step := 1
+		switch {
+		case hasValue && sr.getTypeCode() == "bool" && !isBoolLiteral(value):
+			return &ParseError{Index: i, Token: args[i], Flag: flag, TypeCode: "bool", Err: ArgValueError}
+		case hasValue:
+		case sr.getTypeCode() == "bool" && i+1 < len(args) && isBoolLiteral(args[i+1]):
+			value = args[i+1]
+			step = 2
+		case sr.getTypeCode() == "bool":
 			value = "true"
+		case sr.getTypeCode() == "count":
+			n, _ := strconv.Atoi(p.argPairs[flag])
+			value = strconv.Itoa(n + 1)
+		case i+1 < len(args):
+			value = args[i+1]
+			step = 2
+		default:
+			return &ParseError{Index: i, Token: args[i], Flag: flag, TypeCode: sr.getTypeCode(), Err: MissingValueError}
 		}
 		p.argPairs[flag] = value
 		i += step
@@ -113,6 +276,44 @@ func (p *Parser) parse(aArgString string) error {
 	return nil
 }
 
+// parseSwitch parses a negated bool flag such as no-l or a repeated count
+// flag such as vvv.
+func (p *Parser) parseSwitch(flag string) bool {
+	if strings.HasPrefix(flag, "no-") {
+		if sr, err := p.schema.getSchemaRule(flag[3:]); err == nil && sr.getTypeCode() == "bool" {
+			p.argPairs[sr.getFlag()] = "false"
+			return true
+		}
+	}
+
+	if flag == "" || strings.Count(flag, flag[:1]) != len(flag) {
+		return false
+	}
+	sr, err := p.schema.getSchemaRule(flag[:1])
+	if err != nil || sr.getTypeCode() != "count" {
+		return false
+	}
+	n, _ := strconv.Atoi(p.argPairs[sr.getFlag()])
+	p.argPairs[sr.getFlag()] = strconv.Itoa(n + len(flag))
+	return true
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1":
+		return true, true
+	case "false", "no", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func isBoolLiteral(s string) bool {
+	_, ok := parseBool(s)
+	return ok
+}
+
 func (p *Parser) GetStringArg(flag string) string {
 	if stringArg, ok := p.argPairs[flag]; ok {
 		return stringArg
@@ -122,11 +323,12 @@ func (p *Parser) GetStringArg(flag string) string {
 }
 
 func (p *Parser) GetBoolArg(flag string) bool {
-	argv := p.GetStringArg(flag)
-	if argv == "true" {
-		return true
-	}
-	return false
+	b, _ := parseBool(p.GetStringArg(flag))
+	return b
+}
+
+func (p *Parser) GetCountArg(flag string) int {
+	return p.GetIntArg(flag)
 }
 
 func (p *Parser) GetIntArg(flag string) int {
@@ -240,7 +240,158 @@ func assertError(t *testing.T, got, want error) {
 		t.Fatalf("didn't get an error but wanted one")
 	}
 
+	if err, ok := got.(*ParseError); ok {
+		got = err.Err
+	}
 	if got != want {
 		t.Errorf("got '%s', want '%s'", got, want)
 	}
 }
+
+func TestSplitArgs(t *testing.T) {
+	splitTests := []struct {
+		argString string
+		want      []string
+		err       error
+	}{
+		{"-l -p 8080", []string{"-l", "-p", "8080"}, nil},
+		{`-d "/my dir"`, []string{"-d", "/my dir"}, nil},
+		{`-d '/my "dir"'`, []string{"-d", `/my "dir"`}, nil},
+		{`-d /my\ dir -e ""`, []string{"-d", "/my dir", "-e", ""}, nil},
+		{`-d "/my dir`, nil, UnterminatedQuoteError},
+		{`-d dir\`, nil, TrailingEscapeError},
+	}
+
+	for _, tt := range splitTests {
+		got, err := splitArgs(tt.argString)
+		if tt.err != nil {
+			assertError(t, err, tt.err)
+			continue
+		}
+		assertNoError(t, err)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	aSchemaString := "l:bool:false p:int:80 d:string:./logs"
+
+	t.Run("test quoted arg string", func(t *testing.T) {
+		aParser := newParser(aSchemaString)
+		assertNil(t, aParser)
+		assertNoError(t, aParser.parse(`-p 8080 -d "/my dir"`))
+		testGetArgValue(t, aParser, []flagTest{
+			{"p", "int", 8080},
+			{"d", "string", "/my dir"},
+		})
+	})
+
+	t.Run("test arg slice", func(t *testing.T) {
+		aParser := newParser(aSchemaString)
+		assertNil(t, aParser)
+		assertNoError(t, aParser.parseArgs([]string{"-d", "/my dir", "-p", "8080"}))
+		testGetArgValue(t, aParser, []flagTest{
+			{"p", "int", 8080},
+			{"d", "string", "/my dir"},
+		})
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	aParser := newParser("l:bool p:int d:string")
+
+	err := aParser.parseArgs([]string{"-l", "-x", "1"})
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("got %v, want a *ParseError", err)
+	}
+	if parseErr.Index != 1 || parseErr.Token != "-x" || parseErr.Flag != "x" || parseErr.Err != FlagNotExistError {
+		t.Errorf("got %+v", parseErr)
+	}
+
+	err = aParser.parseArgs([]string{"-l", "-p"})
+	parseErr, ok = err.(*ParseError)
+	if !ok {
+		t.Fatalf("got %v, want a *ParseError", err)
+	}
+	if parseErr.Index != 1 || parseErr.TypeCode != "int" || parseErr.Err != MissingValueError {
+		t.Errorf("got %+v", parseErr)
+	}
+	want := `argument 1 "-p": missing argument value, want int`
+	if parseErr.Error() != want {
+		t.Errorf("got '%s', want '%s'", parseErr.Error(), want)
+	}
+
+	assertNoError(t, aParser.parseArgs([]string{"-p", "8080", "-l"}))
+	if !aParser.GetBoolArg("l") {
+		t.Errorf("got false, want true")
+	}
+
+	suggestParser := newParser("port:int dir:string")
+	err = suggestParser.parseArgs([]string{"-prt", "80"})
+	parseErr, ok = err.(*ParseError)
+	if !ok {
+		t.Fatalf("got %v, want a *ParseError", err)
+	}
+	if parseErr.Suggestion != "port" {
+		t.Errorf("got '%s', want 'port'", parseErr.Suggestion)
+	}
+}
+
+func TestBoolArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		log     bool
+		verbose int
+		port    int
+	}{
+		{[]string{"-l", "false", "-p", "8080"}, false, 0, 8080},
+		{[]string{"--l", "no"}, false, 0, 0},
+		{[]string{"-l=false", "-p=80"}, false, 0, 80},
+		{[]string{"-l", "1"}, true, 0, 0},
+		{[]string{"-l", "-p", "8080"}, true, 0, 8080},
+		{[]string{"--no-l"}, false, 0, 0},
+		{[]string{"-vvv", "-l"}, true, 3, 0},
+		{[]string{"-v", "-v"}, true, 2, 0},
+		{[]string{"-v=5"}, true, 5, 0},
+	}
+
+	for _, tt := range tests {
+		aParser := newParser("l:bool v:count p:int")
+		assertNoError(t, aParser.parseArgs(tt.args))
+		if got := aParser.GetBoolArg("l"); got != tt.log {
+			t.Errorf("%v: got %t, want %t", tt.args, got, tt.log)
+		}
+		if got := aParser.GetCountArg("v"); got != tt.verbose {
+			t.Errorf("%v: got %d, want %d", tt.args, got, tt.verbose)
+		}
+		if got := aParser.GetIntArg("p"); got != tt.port {
+			t.Errorf("%v: got %d, want %d", tt.args, got, tt.port)
+		}
+	}
+
+	aParser := newParser("l:bool v:count")
+	assertError(t, aParser.parseArgs([]string{"-vvl"}), FlagNotExistError)
+
+	err := aParser.parseArgs([]string{"-v", "-l=maybe"})
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("got %v, want a *ParseError", err)
+	}
+	if parseErr.Index != 1 || parseErr.Flag != "l" || parseErr.TypeCode != "bool" {
+		t.Errorf("got %+v, want index 1 and bool flag l", parseErr)
+	}
+	assertError(t, err, ArgValueError)
+
+	aParser = newParser("l:bool v:count")
+	assertNoError(t, aParser.parseArgs([]string{"-vv", "--no-l"}))
+	assertNoError(t, aParser.parseArgs([]string{"-v"}))
+	if got := aParser.GetCountArg("v"); got != 1 {
+		t.Errorf("got %d, want 1 after parsing again", got)
+	}
+	if got := aParser.GetBoolArg("l"); !got {
+		t.Errorf("got %t, want the default true after parsing again", got)
+	}
+}
@@ -2,6 +2,8 @@ package args2
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -9,17 +11,177 @@ import (
 var ErrWrongSchemaRule = errors.New("wrong shcemule rule")
 var ErrNotSupportArgumentType = errors.New("not support argument type")
 var ErrorFlagNotExist = errors.New("flag not exist")
+var ErrDuplicateFlag = errors.New("flag defined twice")
+var ErrMissingValue = errors.New("missing flag value")
+
+// ErrHelp is returned when -h or --help is given and the schema doesn't
+// define them.
+var ErrHelp = errors.New("help requested")
+
+// SchemaError reports a schema rule that can't be used. Column is where
+// the error is in a schema string, counting from 1, or 0 when the schema
+// isn't given as a string.
+type SchemaError struct {
+	Rule   string
+	Err    error
+	Column int
+}
+
+func (e *SchemaError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("schema column %d: rule %q: %v", e.Column, e.Rule, e.Err)
+	}
+	return fmt.Sprintf("schema rule %q: %v", e.Rule, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// FlagError reports a flag that isn't in the schema or is missing its
+// value. Index and Token locate the argument it was given by, Token is
+// empty when it wasn't given by an argument.
+type FlagError struct {
+	Flag       string
+	Err        error
+	Index      int
+	Token      string
+	TypeCode   string
+	Suggestion string
+}
+
+func (e *FlagError) Error() string {
+	msg := fmt.Sprintf("flag %s: %v", typedFlag(e.Flag, e.Token), e.Err)
+	if e.Token != "" {
+		msg = fmt.Sprintf("argument %d %q: %s", e.Index, e.Token, msg)
+	}
+	if e.TypeCode != "" {
+		msg += ", want " + e.TypeCode
+	}
+	if e.Suggestion != "" {
+		msg += ", did you mean " + e.Suggestion + "?"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// typedFlag writes flag with the dashes of token, or like a suggestion
+// when it wasn't given by an argument.
+func typedFlag(flag, token string) string {
+	switch {
+	case strings.HasPrefix(token, "--"):
+		return "--" + flag
+	case token != "" || len(flag) == 1:
+		return "-" + flag
+	default:
+		return "--" + flag
+	}
+}
+
+// ValueError reports a value that can't be converted to its flag's type.
+// Index and Token locate the argument the value was given by, Index is -1
+// when it comes from another source.
+type ValueError struct {
+	Flag     string
+	TypeCode string
+	Value    string
+	Err      error
+	Index    int
+	Token    string
+}
+
+func (e *ValueError) Error() string {
+	msg := fmt.Sprintf("flag %s: invalid %s value %q: %v", typedFlag(e.Flag, e.Token), e.TypeCode, e.Value, e.Err)
+	if e.Token != "" {
+		msg = fmt.Sprintf("argument %d %q: %s", e.Index, e.Token, msg)
+	}
+	return msg
+}
 
+// Unwrap returns the underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaRule describes a flag: its name, aliases, type, default value and
+// how help output shows it.
 type SchemaRule struct {
 	flag         string
+	aliases      []string
 	typeCode     string
 	defaultValue string
+	description  string
+	placeholder  string
+	group        string
+	constraints  constraints
+	env          string
+	key          string
+	separator    string
+}
+
+// NewSchemaRule creates a rule for the programmatic form of a schema, an
+// empty defaultValue falls back to the type's default.
+func NewSchemaRule(flag, typeCode, defaultValue string) (*SchemaRule, error) {
+	if flag == "" {
+		return nil, &SchemaError{Rule: flag + ":" + typeCode, Err: ErrWrongSchemaRule}
+	}
+	if !isSupportArgType(typeCode) {
+		return nil, &SchemaError{Rule: flag + ":" + typeCode, Err: ErrNotSupportArgumentType}
+	}
+	if defaultValue == "" {
+		defaultValue = getDefaultValue(typeCode)
+	}
+
+	sr := new(SchemaRule)
+	sr.flag = flag
+	sr.typeCode = typeCode
+	sr.defaultValue = defaultValue
+	return sr, nil
+}
+
+// WithAliases adds other names the flag can be given by, such as "port"
+// for "p".
+func (sr *SchemaRule) WithAliases(aliases ...string) *SchemaRule {
+	sr.aliases = append(sr.aliases, aliases...)
+	return sr
+}
+
+// WithDescription sets the text help output describes the flag with.
+func (sr *SchemaRule) WithDescription(description string) *SchemaRule {
+	sr.description = description
+	return sr
+}
+
+// WithPlaceholder sets the name help output shows for the flag's value,
+// such as PORT in "--port PORT". It defaults to the type.
+func (sr *SchemaRule) WithPlaceholder(placeholder string) *SchemaRule {
+	sr.placeholder = placeholder
+	return sr
+}
+
+// WithGroup sets the section help output lists the flag under.
+func (sr *SchemaRule) WithGroup(group string) *SchemaRule {
+	sr.group = group
+	return sr
 }
 
 func (sr *SchemaRule) getFlag() string {
 	return sr.flag
 }
 
+func (sr *SchemaRule) getAliases() []string {
+	return sr.aliases
+}
+
+func (sr *SchemaRule) names() []string {
+	return append([]string{sr.flag}, sr.aliases...)
+}
+
 func (sr *SchemaRule) getTypeCode() string {
 	return sr.typeCode
 }
@@ -28,9 +190,33 @@ func (sr *SchemaRule) getDefaultValue() string {
 	return sr.defaultValue
 }
 
+func (sr *SchemaRule) getDescription() string {
+	return sr.description
+}
+
+func (sr *SchemaRule) getPlaceholder() string {
+	if sr.placeholder == "" && sr.typeCode != "bool" && sr.typeCode != "count" {
+		return sr.typeCode
+	}
+	return sr.placeholder
+}
+
+func (sr *SchemaRule) getGroup() string {
+	return sr.group
+}
+
 func isSupportArgType(typeCode string) bool {
+	if isBuiltinType(typeCode) {
+		return true
+	}
+	_, ok := registeredType(typeCode)
+	return ok
+}
+
+func isBuiltinType(typeCode string) bool {
 	switch typeCode {
-	case "bool", "int", "string", "[string]", "[int]":
+	case "bool", "count", "int", "string", "[string]", "[int]",
+		"float", "uint", "duration", "time", "map", "[float]":
 		return true
 	default:
 		return false
@@ -41,179 +227,456 @@ func getDefaultValue(typeCode string) string {
 	switch typeCode {
 	case "bool":
 		return "false"
-	case "int":
+	case "int", "count", "float", "uint":
 		return "0"
-	case "[string]", "[int]":
-		return "[]"
-	default:
-		return ""
+	case "duration":
+		return "0s"
+	case "[string]", "[int]", "[float]":
+		return emptyList
 	}
-}
-
-func newSchemaRule(aSchemaRuleString string) (*SchemaRule, error) {
-	srData := strings.Split(aSchemaRuleString, ":")
-	if len(srData) > 3 || len(srData) < 2 {
-		return nil, ErrWrongSchemaRule
-	}
-
-	flag := srData[0]
-	typeCode := srData[1]
-	if !isSupportArgType(typeCode) {
-		return nil, ErrNotSupportArgumentType
+	if newValue, ok := registeredType(typeCode); ok {
+		return newValue().String()
 	}
-
-	defaultValue := ""
-	if len(srData) == 3 {
-		defaultValue = srData[2]
-	}
-
-	if defaultValue == "" {
-		defaultValue = getDefaultValue(typeCode)
-	}
-
-	sr := new(SchemaRule)
-	sr.flag = flag
-	sr.typeCode = typeCode
-	sr.defaultValue = defaultValue
-	return sr, nil
+	return ""
 }
 
+// Schema is a set of schema rules, each one reachable by its flag and
+// its aliases.
 type Schema struct {
 	schemaRules map[string]*SchemaRule
+	names       map[string]*SchemaRule
+	flags       []string
+	positionals []*Positional
+	exclusive   [][]string
 }
 
-func newSchema(aSchemaString string) (*Schema, error) {
+// NewSchema creates the programmatic form of a schema.
+func NewSchema(rules ...*SchemaRule) (*Schema, error) {
 	aSchema := new(Schema)
 	aSchema.schemaRules = make(map[string]*SchemaRule, 0)
-	schemaData := strings.Split(aSchemaString, " ")
-	for _, sd := range schemaData {
-		sr, err := newSchemaRule(sd)
-		if err != nil {
+	aSchema.names = make(map[string]*SchemaRule, 0)
+	for _, sr := range rules {
+		if err := aSchema.addSchemaRule(sr); err != nil {
 			return nil, err
 		}
-		aSchema.schemaRules[sr.getFlag()] = sr
 	}
 	return aSchema, nil
 }
 
+func (s *Schema) addSchemaRule(sr *SchemaRule) error {
+	for _, name := range sr.names() {
+		if _, ok := s.names[name]; ok {
+			return &SchemaError{Rule: name, Err: ErrDuplicateFlag}
+		}
+		s.names[name] = sr
+	}
+	s.schemaRules[sr.getFlag()] = sr
+	s.flags = append(s.flags, sr.getFlag())
+	return nil
+}
+
 func (s *Schema) size() int {
 	return len(s.schemaRules)
 }
 
+func (s *Schema) schemaRuleOf(flag string) (*SchemaRule, error) {
+	if sr, ok := s.names[flag]; ok {
+		return sr, nil
+	}
+	return nil, &FlagError{Flag: flag, Err: ErrorFlagNotExist, Suggestion: s.suggest(flag)}
+}
+
 func (s *Schema) typeOf(flag string) (string, error) {
-	if sr, ok := s.schemaRules[flag]; ok {
-		return sr.getTypeCode(), nil
+	sr, err := s.schemaRuleOf(flag)
+	if err != nil {
+		return "", err
 	}
-	return "", ErrorFlagNotExist
+	return sr.getTypeCode(), nil
 }
 
 func (s *Schema) defaultValueOf(flag string) (string, error) {
-	if sr, ok := s.schemaRules[flag]; ok {
-		return sr.getDefaultValue(), nil
+	sr, err := s.schemaRuleOf(flag)
+	if err != nil {
+		return "", err
 	}
-	return "", ErrorFlagNotExist
+	return sr.getDefaultValue(), nil
 }
 
+// Parser parses arguments against a schema such as "l:bool p:int:80 d:string".
 type Parser struct {
-	schema    *Schema
-	arguments map[string]string
+	schema      *Schema
+	arguments   map[string]string
+	positionals map[string][]string
+	args        []string
+	tokens      map[string]token
+	config      map[string]string
+	lookupEnv   func(string) (string, bool)
+	parsed      bool
 }
 
-func newParser(aSchemaString string) (*Parser, error) {
+// NewParser creates a parser for a schema string.
+func NewParser(aSchemaString string) (*Parser, error) {
 	aSchema, err := newSchema(aSchemaString)
 	if err != nil {
 		return nil, err
 	}
+	return NewParserFromSchema(aSchema), nil
+}
+
+// NewParserFromSchema creates a parser for the programmatic form of a
+// schema.
+func NewParserFromSchema(aSchema *Schema) *Parser {
 	aParser := new(Parser)
 	aParser.schema = aSchema
 	aParser.arguments = make(map[string]string, 0)
-	return aParser, nil
+	aParser.config = make(map[string]string, 0)
+	aParser.lookupEnv = os.LookupEnv
+	return aParser
+}
+
+// Parse parses an arguments string such as "-l -p 8080 -d '/my logs'",
+// see SplitArguments for the quoting rules.
+func (p *Parser) Parse(aArgumentsString string) error {
+	argumentsData, err := SplitArguments(aArgumentsString)
+	if err != nil {
+		return err
+	}
+	return p.ParseArgs(argumentsData)
+}
+
+// ParseArgs parses arguments that are already split, such as os.Args[1:].
+// Flags are given as -p, --port, -p=8080 or --port=8080 and single letter
+// bool flags can be clustered as -lvx. A bool flag takes the next argument
+// when it is true, false, yes, no, 1 or 0 and --no-flag sets it false.
+// Every occurrence of a count flag adds one. Other arguments, and every
+// argument after "--", are positional.
+func (p *Parser) ParseArgs(argumentsData []string) error {
+	_, err := p.parseArgs(argumentsData, nil)
+	return err
 }
 
-func (p *Parser) parse(aArgumentsString string) error {
+// parseArgs stops at the first positional argument isCommand accepts and
+// returns its index, or len(argumentsData) when there is none.
+func (p *Parser) parseArgs(argumentsData []string, isCommand func(string) bool) (int, error) {
 	p.arguments = make(map[string]string, 0)
-	argumentsData := strings.Split(aArgumentsString, " ")
-	for i := 0; i < len(argumentsData); {
-		flag := argumentsData[i][1:]
-		typeCode, err := p.schema.typeOf(flag)
+	p.tokens = make(map[string]token, 0)
+	p.parsed = true
+	var positionals []string
+	i := 0
+	for i < len(argumentsData) {
+		argument := argumentsData[i]
+		if argument == "--" {
+			positionals = append(positionals, argumentsData[i+1:]...)
+			i = len(argumentsData)
+			break
+		}
+		if !strings.HasPrefix(argument, "-") || argument == "-" {
+			if isCommand != nil && len(positionals) == 0 && isCommand(argument) {
+				break
+			}
+			positionals = append(positionals, argument)
+			i++
+			continue
+		}
+
+		step, err := p.parseFlag(argumentsData, i)
 		if err != nil {
-			return err
+			return i, err
+		}
+		i += step
+	}
+	if err := p.bindPositionals(positionals); err != nil {
+		return i, err
+	}
+	return i, p.validate()
+}
+
+// parseFlag parses the flag at argumentsData[i] and returns how many
+// arguments it used.
+func (p *Parser) parseFlag(argumentsData []string, i int) (int, error) {
+	argument := argumentsData[i]
+	name := strings.TrimPrefix(argument[1:], "-")
+	value, hasValue := "", false
+	if eq := strings.Index(name, "="); eq >= 0 {
+		name, value, hasValue = name[:eq], name[eq+1:], true
+	}
+
+	if !strings.HasPrefix(argument, "--") {
+		if _, err := p.schema.schemaRuleOf(name); err != nil && p.isCluster(name, hasValue) {
+			switches := name
+			if hasValue {
+				switches, name = name[:len(name)-1], name[len(name)-1:]
+			}
+			for _, c := range switches {
+				sr, _ := p.schema.schemaRuleOf(string(c))
+				p.setSwitch(sr, i, argument)
+			}
+			if !hasValue {
+				return 1, nil
+			}
 		}
+	}
 
-		step := 2
-		value := ""
-		if typeCode == "bool" && i+1 < len(argumentsData) && argumentsData[i+1] != "true" {
-			step = 1
-			value = "true"
-		} else {
+	sr, err := p.schema.schemaRuleOf(name)
+	if err != nil && (name == "h" || name == "help") {
+		return 0, ErrHelp
+	}
+	if err != nil && strings.HasPrefix(name, "no-") && !hasValue {
+		if negated, negatedErr := p.schema.schemaRuleOf(name[3:]); negatedErr == nil && negated.getTypeCode() == "bool" {
+			p.set(negated, "false", i, argument)
+			return 1, nil
+		}
+	}
+	if err != nil {
+		flagErr := err.(*FlagError)
+		flagErr.Index, flagErr.Token = i, argument
+		return 0, flagErr
+	}
+
+	if hasValue {
+		var err error
+		switch sr.getTypeCode() {
+		case "bool":
+			_, err = parseBool(value)
+		case "count":
+			_, err = parseCount(value)
+		}
+		if err != nil {
+			return 0, &ValueError{Flag: name, TypeCode: sr.getTypeCode(), Value: value, Err: err, Index: i, Token: argument}
+		}
+	}
+
+	step := 1
+	if !hasValue {
+		step = 2
+		switch {
+		case sr.getTypeCode() == "bool" && i+1 < len(argumentsData) && isBoolLiteral(argumentsData[i+1]):
+			value = argumentsData[i+1]
+		case sr.getTypeCode() == "bool" || sr.getTypeCode() == "count":
+			p.setSwitch(sr, i, argument)
+			return 1, nil
+		case i+1 == len(argumentsData):
+			return 0, &FlagError{Flag: name, Err: ErrMissingValue, Index: i, Token: argument, TypeCode: sr.getTypeCode()}
+		default:
 			value = argumentsData[i+1]
 		}
-		i += step
-		p.arguments[flag] = value
 	}
-	return nil
+	p.set(sr, value, i, argument)
+	return step, nil
 }
 
-func (p *Parser) stringValueOf(flag string) (string, error) {
-	if v, ok := p.arguments[flag]; ok {
-		return v, nil
+// token is the argument a flag was given by.
+type token struct {
+	index    int
+	argument string
+}
+
+// set sets the value of a flag, list flags given more than once
+// accumulate their elements.
+func (p *Parser) set(sr *SchemaRule, value string, i int, argument string) {
+	if list, ok := p.arguments[sr.getFlag()]; ok && isListType(sr.getTypeCode()) {
+		value = appendList(list, value, sr.getSeparator())
 	}
+	p.arguments[sr.getFlag()] = value
+	p.tokens[sr.getFlag()] = token{i, argument}
+}
 
-	dv, err := p.schema.defaultValueOf(flag)
-	if err == nil {
-		return dv, nil
+// setSwitch sets a bool flag given without a value, or counts one more
+// occurrence of a count flag.
+func (p *Parser) setSwitch(sr *SchemaRule, i int, argument string) {
+	if sr.getTypeCode() != "count" {
+		p.set(sr, "true", i, argument)
+		return
 	}
-	return "", err
+	n, _ := strconv.Atoi(p.arguments[sr.getFlag()])
+	p.set(sr, strconv.Itoa(n+1), i, argument)
 }
 
-func (p *Parser) boolValueOf(flag string) (bool, error) {
-	v, err := p.stringValueOf(flag)
-	if err != nil {
-		return false, err
+// valueError locates an invalid value of flag in the arguments.
+func (p *Parser) valueError(flag, typeCode, value string, err error) *ValueError {
+	valueErr := &ValueError{Flag: flag, TypeCode: typeCode, Value: value, Err: err, Index: -1}
+	if sr, lookupErr := p.schema.schemaRuleOf(flag); lookupErr == nil {
+		if _, source := p.resolve(sr); source == SourceFlag {
+			t := p.tokens[sr.getFlag()]
+			valueErr.Index, valueErr.Token = t.index, t.argument
+		}
+	}
+	return valueErr
+}
+
+// isCluster tells whether every letter of name is a bool or count flag,
+// such as -lvx or -vvv. With a value the last letter may be any flag, as
+// in -lp=8080.
+func (p *Parser) isCluster(name string, hasValue bool) bool {
+	if len(name) < 2 {
+		return false
+	}
+	for i, c := range name {
+		typeCode, err := p.schema.typeOf(string(c))
+		if err != nil {
+			return false
+		}
+		if typeCode != "bool" && typeCode != "count" && !(hasValue && i == len(name)-1) {
+			return false
+		}
 	}
+	return true
+}
 
-	if v == "true" {
+// parseBool accepts true, false, yes, no, 1 and 0 in any case.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1":
 		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	default:
+		return false, strconv.ErrSyntax
 	}
+}
 
-	return false, nil
+func isBoolLiteral(s string) bool {
+	_, err := parseBool(s)
+	return err == nil
 }
 
-func (p *Parser) intValueOf(flag string) (int, error) {
-	v, err := p.stringValueOf(flag)
+// StringValueOf returns the value of flag from the first source that has
+// it, see SourceOf. flag may be any of its aliases.
+func (p *Parser) StringValueOf(flag string) (string, error) {
+	sr, err := p.schema.schemaRuleOf(flag)
 	if err != nil {
-		return 0, err
+		return "", err
+	}
+	v, _ := p.resolve(sr)
+	return v, nil
+}
+
+// BoolValueOf returns the value of a bool flag.
+func (p *Parser) BoolValueOf(flag string) (bool, error) {
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return false, err
 	}
 
-	intv, err := strconv.Atoi(v)
+	b, err := parseBool(v)
+	if err != nil {
+		return false, p.valueError(flag, "bool", v, err)
+	}
+	return b, nil
+}
+
+// CountValueOf returns how often a count flag is given, such as 3 for
+// -vvv, or the number given by --verbose=3.
+func (p *Parser) CountValueOf(flag string) (int, error) {
+	v, err := p.StringValueOf(flag)
 	if err != nil {
 		return 0, err
 	}
-	return intv, nil
+
+	n, err := parseCount(v)
+	if err != nil {
+		return 0, p.valueError(flag, "count", v, err)
+	}
+	return n, nil
+}
+
+// parseCount accepts a number of occurrences, negative ones are out of
+// range.
+func parseCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err == nil && n < 0 {
+		err = strconv.ErrRange
+	}
+	return n, err
 }
 
-func (p *Parser) stringListOf(flag string) (result []string, err error) {
-	v, err := p.stringValueOf(flag)
+// IntValueOf returns the value of an int flag.
+func (p *Parser) IntValueOf(flag string) (int, error) {
+	v, err := p.StringValueOf(flag)
 	if err != nil {
-		return
+		return 0, err
 	}
 
-	result = strings.Split(v, ",")
+	intv, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, p.valueError(flag, "int", v, err)
+	}
+	return intv, nil
+}
+
+// StringListOf returns the value of a [string] flag.
+func (p *Parser) StringListOf(flag string) (result []string, err error) {
+	result, _, err = p.listOf(flag)
 	return
 }
 
-func (p *Parser) intListOf(flag string) (result []int, err error) {
-	v, err := p.stringValueOf(flag)
+// IntListOf returns the value of an [int] flag.
+func (p *Parser) IntListOf(flag string) (result []int, err error) {
+	elements, v, err := p.listOf(flag)
 	if err != nil {
 		return
 	}
 
-	for _, n := range strings.Split(v, ",") {
+	for _, n := range elements {
 		in, err := strconv.Atoi(n)
 		if err != nil {
-			return []int{}, err
+			return []int{}, p.valueError(flag, "[int]", v, err)
 		}
 		result = append(result, in)
 	}
 	return
 }
+
+// suggest returns the defined flag nearest to name by edit distance, or ""
+// when none is near enough to be a typo.
+func (s *Schema) suggest(name string) string {
+	best, bestDistance := "", len(name)/3
+	if bestDistance < 1 {
+		bestDistance = 1
+	}
+	for other := range s.names {
+		d := editDistance(name, other)
+		if d < len(name) && (d < bestDistance || (d == bestDistance && (best == "" || other < best))) {
+			best, bestDistance = other, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return typedFlag(best, "")
+}
+
+// editDistance counts the insertions, deletions, substitutions and
+// transpositions of adjacent letters turning a into b.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(n int, others ...int) int {
+	for _, o := range others {
+		if o < n {
+			n = o
+		}
+	}
+	return n
+}
@@ -1,8 +1,18 @@
 package args2
 
 import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSchemaRule(t *testing.T) {
@@ -97,7 +107,7 @@ type argument struct {
 func TestParser(t *testing.T) {
 	t.Run("bad schema", func(t *testing.T) {
 		aSchemaString := "l:bool p d:string"
-		aParser, err := newParser(aSchemaString)
+		aParser, err := NewParser(aSchemaString)
 		if aParser != nil {
 			t.Errorf("didn't get nil but want nil")
 		}
@@ -106,7 +116,7 @@ func TestParser(t *testing.T) {
 
 	t.Run("simple good schema", func(t *testing.T) {
 		aSchemaString := "l:bool p:int:80 d:string"
-		aParser, err := newParser(aSchemaString)
+		aParser, err := NewParser(aSchemaString)
 		assertNoError(t, err)
 		if aParser == nil {
 			t.Errorf("got nil but didn't want nil")
@@ -141,7 +151,7 @@ func TestParser(t *testing.T) {
 
 	t.Run("composite good schema", func(t *testing.T) {
 		aSchemaString := "l:bool p:[int] d:[string]"
-		aParser, err := newParser(aSchemaString)
+		aParser, err := NewParser(aSchemaString)
 		assertNoError(t, err)
 		if aParser == nil {
 			t.Errorf("got nil but didn't want nil")
@@ -169,22 +179,22 @@ func TestParser(t *testing.T) {
 
 func testParse(t *testing.T, name, argumentsString string, aParser *Parser, wantArguments []argument) {
 	t.Run(name, func(t *testing.T) {
-		err := aParser.parse(argumentsString)
+		err := aParser.Parse(argumentsString)
 		assertNoError(t, err)
 
 		var v interface{}
 		for _, tt := range wantArguments {
 			switch tt.typeCode {
 			case "string":
-				v, err = aParser.stringValueOf(tt.flag)
+				v, err = aParser.StringValueOf(tt.flag)
 			case "bool":
-				v, err = aParser.boolValueOf(tt.flag)
+				v, err = aParser.BoolValueOf(tt.flag)
 			case "int":
-				v, err = aParser.intValueOf(tt.flag)
+				v, err = aParser.IntValueOf(tt.flag)
 			case "[string]":
-				v, err = aParser.stringListOf(tt.flag)
+				v, err = aParser.StringListOf(tt.flag)
 			case "[int]":
-				v, err = aParser.intListOf(tt.flag)
+				v, err = aParser.IntListOf(tt.flag)
 			default:
 				t.Errorf("not support type")
 				return
@@ -218,14 +228,1158 @@ func assertError(t *testing.T, got, want error) {
 	if got == nil {
 		t.Fatalf("did not get an error but want one")
 	}
-	if got != want {
+	if cause(got) != want {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
 
+func cause(err error) error {
+	for {
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return err
+		}
+		err = wrapper.Unwrap()
+	}
+}
+
 func assertStrings(t *testing.T, got, want string) {
 	t.Helper()
 	if got != want {
 		t.Errorf("got '%s', want '%s'", got, want)
 	}
 }
+
+func TestErrors(t *testing.T) {
+	t.Run("schema error", func(t *testing.T) {
+		_, err := NewParser("l:bool p:complex")
+		schemaErr, ok := err.(*SchemaError)
+		if !ok {
+			t.Fatalf("got %v, want a *SchemaError", err)
+		}
+		assertStrings(t, schemaErr.Rule, "p:complex")
+		assertError(t, err, ErrNotSupportArgumentType)
+	})
+
+	t.Run("flag error", func(t *testing.T) {
+		aParser, err := NewParser("l:bool p:int")
+		assertNoError(t, err)
+
+		err = aParser.Parse("-l -x 1")
+		flagErr, ok := err.(*FlagError)
+		if !ok {
+			t.Fatalf("got %v, want a *FlagError", err)
+		}
+		assertStrings(t, flagErr.Flag, "x")
+		assertError(t, err, ErrorFlagNotExist)
+	})
+
+	t.Run("value error", func(t *testing.T) {
+		aParser, err := NewParser("p:int g:[int]")
+		assertNoError(t, err)
+		assertNoError(t, aParser.Parse("-p abc -g 1,b"))
+
+		_, err = aParser.IntValueOf("p")
+		valueErr, ok := err.(*ValueError)
+		if !ok {
+			t.Fatalf("got %v, want a *ValueError", err)
+		}
+		assertStrings(t, valueErr.Flag, "p")
+		assertStrings(t, valueErr.TypeCode, "int")
+		assertStrings(t, valueErr.Value, "abc")
+
+		_, err = aParser.IntListOf("g")
+		if _, ok := err.(*ValueError); !ok {
+			t.Errorf("got %v, want a *ValueError", err)
+		}
+	})
+
+	t.Run("no arguments", func(t *testing.T) {
+		aParser, err := NewParser("p:int:80")
+		assertNoError(t, err)
+		assertNoError(t, aParser.Parse(""))
+
+		v, err := aParser.IntValueOf("p")
+		assertNoError(t, err)
+		assertEqual(t, v, 80)
+	})
+}
+
+func TestSplitArguments(t *testing.T) {
+	splitTests := []struct {
+		argumentsString string
+		want            []string
+		err             error
+	}{
+		{"-l -p 8080", []string{"-l", "-p", "8080"}, nil},
+		{"  -l   -p\t8080 ", []string{"-l", "-p", "8080"}, nil},
+		{`-d "/my dir"`, []string{"-d", "/my dir"}, nil},
+		{`-d '/my "dir"'`, []string{"-d", `/my "dir"`}, nil},
+		{`-d "say \"hi\" \\ \n"`, []string{"-d", `say "hi" \ \n`}, nil},
+		{`-d /my\ dir`, []string{"-d", "/my dir"}, nil},
+		{`-d "" -e ''`, []string{"-d", "", "-e", ""}, nil},
+		{`-d a"b c"'d'`, []string{"-d", "ab cd"}, nil},
+		{"", nil, nil},
+		{`-d "/my dir`, nil, ErrUnterminatedQuote},
+		{`-d '/my dir`, nil, ErrUnterminatedQuote},
+		{`-d dir\`, nil, ErrTrailingEscape},
+	}
+
+	for _, tt := range splitTests {
+		got, err := SplitArguments(tt.argumentsString)
+		if tt.err != nil {
+			assertError(t, err, tt.err)
+			continue
+		}
+		assertNoError(t, err)
+		assertEqual(t, got, tt.want)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	aParser, err := NewParser("l:bool p:int:80 d:string:./logs")
+	assertNoError(t, err)
+
+	t.Run("quoted string", func(t *testing.T) {
+		testParse(t, "quoted", `-p 8080 -d "/my dir"`, aParser, []argument{
+			{"d", "string", "/my dir", nil},
+			{"p", "int", 8080, nil},
+		})
+	})
+
+	t.Run("argv slice", func(t *testing.T) {
+		assertNoError(t, aParser.ParseArgs([]string{"-d", "", "-l", "-p", "8080"}))
+		v, err := aParser.StringValueOf("d")
+		assertNoError(t, err)
+		assertEqual(t, v, "")
+		b, err := aParser.BoolValueOf("l")
+		assertNoError(t, err)
+		assertEqual(t, b, true)
+	})
+}
+
+func TestLongOptions(t *testing.T) {
+	aParser, err := NewParser("l|log:bool v:bool x:bool p|port:int:80 d|dir:string:./logs")
+	assertNoError(t, err)
+
+	parseTests := []struct {
+		name      string
+		arguments []string
+		want      []argument
+	}{
+		{"long", []string{"--port", "8080", "--log"}, []argument{
+			{"p", "int", 8080, nil},
+			{"port", "int", 8080, nil},
+			{"l", "bool", true, nil},
+		}},
+		{"long inline value", []string{"--port=8080", "--dir=/usr/logs"}, []argument{
+			{"p", "int", 8080, nil},
+			{"dir", "string", "/usr/logs", nil},
+		}},
+		{"short inline value", []string{"-p=8080", "-d=a=b"}, []argument{
+			{"port", "int", 8080, nil},
+			{"d", "string", "a=b", nil},
+		}},
+		{"bool cluster", []string{"-lvx", "-p", "8080"}, []argument{
+			{"l", "bool", true, nil},
+			{"v", "bool", true, nil},
+			{"x", "bool", true, nil},
+			{"p", "int", 8080, nil},
+		}},
+		{"cluster with inline value", []string{"-lp=8080"}, []argument{
+			{"l", "bool", true, nil},
+			{"p", "int", 8080, nil},
+		}},
+		{"bool at end", []string{"-p", "8080", "-v"}, []argument{
+			{"v", "bool", true, nil},
+			{"l", "bool", false, nil},
+		}},
+	}
+
+	for _, tt := range parseTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertNoError(t, aParser.ParseArgs(tt.arguments))
+			for _, a := range tt.want {
+				switch a.typeCode {
+				case "bool":
+					got, err := aParser.BoolValueOf(a.flag)
+					assertNoError(t, err)
+					assertEqual(t, got, a.value)
+				case "int":
+					got, err := aParser.IntValueOf(a.flag)
+					assertNoError(t, err)
+					assertEqual(t, got, a.value)
+				default:
+					got, err := aParser.StringValueOf(a.flag)
+					assertNoError(t, err)
+					assertEqual(t, got, a.value)
+				}
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name      string
+		arguments []string
+	}{
+		{"unknown long", []string{"--verbose"}},
+		{"cluster with non bool", []string{"-lp"}},
+		{"cluster with non bool inside", []string{"-pl=true"}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertError(t, aParser.ParseArgs(tt.arguments), ErrorFlagNotExist)
+		})
+	}
+}
+
+func TestNewSchema(t *testing.T) {
+	port, err := NewSchemaRule("p", "int", "80")
+	assertNoError(t, err)
+	log, err := NewSchemaRule("l", "bool", "")
+	assertNoError(t, err)
+
+	aSchema, err := NewSchema(port.WithAliases("port"), log)
+	assertNoError(t, err)
+	aParser := NewParserFromSchema(aSchema)
+	assertNoError(t, aParser.Parse("--port 8080 -l"))
+	p, err := aParser.IntValueOf("p")
+	assertNoError(t, err)
+	assertEqual(t, p, 8080)
+
+	_, err = NewSchemaRule("p", "complex", "")
+	assertError(t, err, ErrNotSupportArgumentType)
+
+	_, err = newSchema("p|port:int l|port:bool")
+	assertError(t, err, ErrDuplicateFlag)
+}
+
+func TestPositionals(t *testing.T) {
+	aParser, err := NewParser("v:bool o:string <src> [dst] [files]...")
+	assertNoError(t, err)
+
+	t.Run("variadic", func(t *testing.T) {
+		assertNoError(t, aParser.ParseArgs([]string{"-v", "a", "-o", "out", "b", "c", "d"}))
+		src, err := aParser.PositionalOf("src")
+		assertNoError(t, err)
+		assertEqual(t, src, "a")
+		dst, err := aParser.PositionalOf("dst")
+		assertNoError(t, err)
+		assertEqual(t, dst, "b")
+		files, err := aParser.PositionalsOf("files")
+		assertNoError(t, err)
+		assertEqual(t, files, []string{"c", "d"})
+		assertEqual(t, aParser.Args(), []string{"a", "b", "c", "d"})
+	})
+
+	t.Run("optional missing", func(t *testing.T) {
+		assertNoError(t, aParser.ParseArgs([]string{"a"}))
+		dst, err := aParser.PositionalOf("dst")
+		assertNoError(t, err)
+		assertEqual(t, dst, "")
+	})
+
+	t.Run("terminator", func(t *testing.T) {
+		assertNoError(t, aParser.ParseArgs([]string{"-v", "--", "-o", "-", "--"}))
+		assertEqual(t, aParser.Args(), []string{"-o", "-", "--"})
+		o, err := aParser.StringValueOf("o")
+		assertNoError(t, err)
+		assertEqual(t, o, "")
+	})
+
+	t.Run("required missing", func(t *testing.T) {
+		assertError(t, aParser.ParseArgs([]string{"-v"}), ErrMissingArgument)
+	})
+
+	t.Run("not declared", func(t *testing.T) {
+		_, err := aParser.PositionalOf("out")
+		assertError(t, err, ErrPositionalNotExist)
+	})
+
+	t.Run("unexpected", func(t *testing.T) {
+		noPositionals, err := NewParser("p:int")
+		assertNoError(t, err)
+		assertError(t, noPositionals.ParseArgs([]string{"-p", "1", "8080"}), ErrUnexpectedArgument)
+	})
+
+	wrongSchemas := []string{
+		"[a] <b>",
+		"<a>... <b>",
+		"<a> <a>",
+		"<a",
+	}
+	for _, s := range wrongSchemas {
+		_, err := NewParser(s)
+		if err == nil {
+			t.Errorf("schema %q: got nil, want an error", s)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	rootSchema, err := newSchema("c:string:tool.json")
+	assertNoError(t, err)
+	buildSchema, err := newSchema("v:bool <files>...")
+	assertNoError(t, err)
+	remoteSchema, err := NewSchema()
+	assertNoError(t, err)
+	addSchema, err := newSchema("f:bool <name> <url>")
+	assertNoError(t, err)
+
+	root := NewCommand("tool", "A tool.", rootSchema)
+	build := NewCommand("build", "Build files.", buildSchema)
+	remote := NewCommand("remote", "Manage remotes.", remoteSchema)
+	add := NewCommand("add", "Add a remote.", addSchema)
+	assertNoError(t, root.AddCommand(build, remote))
+	assertNoError(t, remote.AddCommand(add))
+	assertError(t, root.AddCommand(NewCommand("build", "", buildSchema)), ErrDuplicateFlag)
+
+	t.Run("subcommand", func(t *testing.T) {
+		c, err := root.ParseArgs([]string{"-c", "my.json", "build", "-v", "file1", "file2"})
+		assertNoError(t, err)
+		assertEqual(t, c, build)
+		config, err := root.Parser().StringValueOf("c")
+		assertNoError(t, err)
+		assertEqual(t, config, "my.json")
+		files, err := c.Parser().PositionalsOf("files")
+		assertNoError(t, err)
+		assertEqual(t, files, []string{"file1", "file2"})
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		c, err := root.Parse("remote add -f origin https://example.com")
+		assertNoError(t, err)
+		assertEqual(t, c.Path(), "tool remote add")
+		url, err := c.Parser().PositionalOf("url")
+		assertNoError(t, err)
+		assertEqual(t, url, "https://example.com")
+	})
+
+	t.Run("no subcommand", func(t *testing.T) {
+		c, err := root.ParseArgs(nil)
+		assertNoError(t, err)
+		assertEqual(t, c, root)
+	})
+
+	t.Run("unknown subcommand", func(t *testing.T) {
+		_, err := root.ParseArgs([]string{"deploy"})
+		assertError(t, err, ErrUnexpectedArgument)
+	})
+
+	t.Run("subcommand error", func(t *testing.T) {
+		c, err := root.Parse("remote add origin")
+		assertError(t, err, ErrMissingArgument)
+		assertEqual(t, c, add)
+	})
+
+	t.Run("help", func(t *testing.T) {
+		want := `Usage: tool remote add [flags] <name> <url>
+
+Add a remote.
+
+Arguments:
+  <name>
+  <url>
+
+Flags:
+  -f
+`
+		assertEqual(t, add.Help(), want)
+		if !strings.Contains(root.Help(), "  build      Build files.\n") {
+			t.Errorf("root help doesn't list build:\n%s", root.Help())
+		}
+	})
+}
+
+func TestBind(t *testing.T) {
+	type database struct {
+		Host string `arg:"host" default:"localhost"`
+		Port int    `arg:"port" default:"5432"`
+	}
+	type config struct {
+		Log      bool     `arg:"l,log" help:"enable logging"`
+		Port     int      `arg:"p,port" default:"80"`
+		Dir      string   `arg:"d" default:"./logs"`
+		Names    []string `arg:"n"`
+		Ports    []int    `arg:"ports" default:"1,2"`
+		Database database `arg:"db"`
+		Server   struct {
+			Name string `arg:"name"`
+		}
+		Src     string   `arg:"<src>"`
+		Rest    []string `arg:"[rest]..."`
+		Ignored string
+	}
+
+	var cfg config
+	err := Bind(&cfg, []string{"--log", "-p", "8080", "--db-port=3306", "--name", "web", "-n", "a,b", "in", "x", "y"})
+	assertNoError(t, err)
+	want := config{
+		Log:      true,
+		Port:     8080,
+		Dir:      "./logs",
+		Names:    []string{"a", "b"},
+		Ports:    []int{1, 2},
+		Database: database{"localhost", 3306},
+		Src:      "in",
+		Rest:     []string{"x", "y"},
+	}
+	want.Server.Name = "web"
+	assertEqual(t, cfg, want)
+
+	t.Run("errors", func(t *testing.T) {
+		assertError(t, Bind(&cfg, []string{"-p", "http", "in"}), strconv.ErrSyntax)
+		assertError(t, Bind(&cfg, []string{"--verbose", "in"}), ErrorFlagNotExist)
+		assertError(t, Bind(&cfg, nil), ErrMissingArgument)
+		assertError(t, Bind(cfg, nil), ErrNotStructPointer)
+
+		var wrongType struct {
+			Rate complex128 `arg:"r"`
+		}
+		assertError(t, Bind(&wrongType, nil), ErrNotSupportArgumentType)
+
+		var duplicate struct {
+			A bool `arg:"a"`
+			B bool `arg:"b,a"`
+		}
+		assertError(t, Bind(&duplicate, nil), ErrDuplicateFlag)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		aSchema, err := SchemaOf(&cfg)
+		assertNoError(t, err)
+		help := NewCommand("tool", "", aSchema).Help()
+		if !strings.Contains(help, "  -l, --log         enable logging\n") {
+			t.Errorf("help doesn't describe -l:\n%s", help)
+		}
+	})
+}
+
+func TestHelp(t *testing.T) {
+	aSchema, err := newSchema(`l|log:bool "enable logging" p|port=PORT:int:80 "port to listen on" ` +
+		`@Database host=HOST:string:localhost "database host" <files>... "files to serve"`)
+	assertNoError(t, err)
+	c := NewCommand("serve", "Serve files.", aSchema)
+
+	t.Run("text", func(t *testing.T) {
+		want := `Usage: serve [flags] <files>...
+
+Serve files.
+
+Arguments:
+  <files>...       files to serve
+
+Flags:
+  -l, --log        enable logging
+  -p, --port PORT  port to listen on (default 80)
+
+Database:
+  --host HOST      database host (default localhost)
+`
+		assertEqual(t, c.Help(), want)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		want := "# serve\n\nServe files.\n\n## Usage\n\n```\nserve [flags] <files>...\n```\n" +
+			"\n## Arguments\n\n| Name | Description |\n| --- | --- |\n| `<files>...` | files to serve |\n" +
+			"\n## Flags\n\n| Name | Description |\n| --- | --- |\n" +
+			"| `-l, --log` | enable logging |\n| `-p, --port PORT` | port to listen on (default 80) |\n" +
+			"\n## Database\n\n| Name | Description |\n| --- | --- |\n| `--host HOST` | database host (default localhost) |\n"
+		assertEqual(t, c.Markdown(), want)
+	})
+
+	t.Run("man page", func(t *testing.T) {
+		want := `.TH SERVE 1
+.SH NAME
+serve \- Serve files.
+.SH SYNOPSIS
+\fBserve\fR [flags] <files>...
+.SH ARGUMENTS
+.TP
+\fB<files>...\fR
+files to serve
+.SH FLAGS
+.TP
+\fB\-l\fR, \fB\-\-log\fR
+enable logging
+.TP
+\fB\-p\fR, \fB\-\-port\fR \fIPORT\fR
+port to listen on (default 80)
+.SH DATABASE
+.TP
+\fB\-\-host\fR \fIHOST\fR
+database host (default localhost)
+`
+		assertEqual(t, c.ManPage(), want)
+	})
+
+	t.Run("programmatic", func(t *testing.T) {
+		port, err := NewSchemaRule("p", "int", "")
+		assertNoError(t, err)
+		aSchema, err := NewSchema(port.WithPlaceholder("N").WithDescription("workers").WithGroup("Tuning"))
+		assertNoError(t, err)
+		want := "Usage: run [flags]\n\nTuning:\n  -p N  workers\n"
+		assertEqual(t, NewCommand("run", "", aSchema).Help(), want)
+	})
+
+	t.Run("help flag", func(t *testing.T) {
+		_, err := c.ParseArgs([]string{"--help"})
+		assertError(t, err, ErrHelp)
+		_, err = c.ParseArgs([]string{"-h"})
+		assertError(t, err, ErrHelp)
+	})
+}
+
+func TestConstraints(t *testing.T) {
+	newRule := func(flag, typeCode string) *SchemaRule {
+		sr, err := NewSchemaRule(flag, typeCode, "")
+		assertNoError(t, err)
+		return sr
+	}
+	aSchema, err := NewSchema(
+		newRule("p", "int").WithAliases("port").WithRange(1, 65535),
+		newRule("m", "string").WithEnum("debug", "release").Required(),
+		newRule("n", "string").WithPattern(regexp.MustCompile(`^[a-z]+$`)),
+		newRule("t", "[int]").WithLength(1, 2).WithRange(0, math.Inf(1)),
+		newRule("c", "string").Requires("k"),
+		newRule("k", "string"),
+		newRule("j", "bool"),
+		newRule("y", "bool"),
+	)
+	assertNoError(t, err)
+	_, err = aSchema.WithExclusive("j", "y")
+	assertNoError(t, err)
+	aParser := NewParserFromSchema(aSchema)
+
+	assertNoError(t, aParser.ParseArgs([]string{"-m", "debug", "-p", "80", "-t", "1,2", "-c", "a", "-k", "b", "-j"}))
+
+	err = aParser.ParseArgs([]string{"-p", "0", "-n", "Web", "-t", "1,-2,3", "-c", "a", "-j", "-y"})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("got %v, want a *ValidationError", err)
+	}
+	wantErrs := []error{ErrOutOfRange, ErrRequired, ErrPatternMismatch, ErrListLength, ErrOutOfRange, ErrRequires, ErrExclusive}
+	if len(validationErr.Errors) != len(wantErrs) {
+		t.Fatalf("got %v, want %d errors", validationErr, len(wantErrs))
+	}
+	for i, want := range wantErrs {
+		assertError(t, validationErr.Errors[i], want)
+	}
+	assertStrings(t, validationErr.Errors[0].Error(), `flag -p: "0": value out of range (between 1 and 65535)`)
+
+	assertError(t, aParser.ParseArgs([]string{"-m", "test"}), ErrNotAllowed)
+	assertError(t, aParser.ParseArgs([]string{"-m", "debug", "-p", "http"}), strconv.ErrSyntax)
+	_, err = aSchema.WithExclusive("j", "x")
+	assertError(t, err, ErrorFlagNotExist)
+
+	t.Run("long flags", func(t *testing.T) {
+		aSchema, err := NewSchema(
+			newRule("timeout", "int").WithRange(1, 60),
+			newRule("host", "string").Requires("port"),
+			newRule("port", "int"),
+			newRule("json", "bool"),
+			newRule("yaml", "bool"),
+		)
+		assertNoError(t, err)
+		_, err = aSchema.WithExclusive("json", "yaml")
+		assertNoError(t, err)
+
+		err = NewParserFromSchema(aSchema).ParseArgs([]string{"--timeout", "0", "--host", "a", "--json", "--yaml"})
+		validationErr, ok := err.(*ValidationError)
+		if !ok {
+			t.Fatalf("got %v, want a *ValidationError", err)
+		}
+		want := []string{
+			`flag --timeout: "0": value out of range (between 1 and 60)`,
+			`flag --host: flag requires another flag (needs --port)`,
+			`flag --json: flags are mutually exclusive (given with --yaml)`,
+		}
+		if len(validationErr.Errors) != len(want) {
+			t.Fatalf("got %v, want %d errors", validationErr, len(want))
+		}
+		for i, w := range want {
+			assertStrings(t, validationErr.Errors[i].Error(), w)
+		}
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		var cfg struct {
+			Port int    `arg:"p" min:"1" max:"65535"`
+			Mode string `arg:"m" enum:"debug,release" required:"true"`
+			Name string `arg:"n" pattern:"^[a-z]+$"`
+		}
+		assertNoError(t, Bind(&cfg, []string{"-m", "release", "-n", "web"}))
+		assertError(t, Bind(&cfg, []string{"-p", "0"}), ErrOutOfRange)
+
+		var wrongPattern struct {
+			Name string `arg:"n" pattern:"("`
+		}
+		if err := Bind(&wrongPattern, nil); err == nil {
+			t.Errorf("got nil, want an error")
+		}
+	})
+}
+
+type level int
+
+func (l *level) Set(s string) error {
+	for i, name := range []string{"debug", "info", "warn"} {
+		if s == name {
+			*l = level(i)
+			return nil
+		}
+	}
+	return errors.New("unknown level")
+}
+
+func (l *level) String() string {
+	return []string{"debug", "info", "warn"}[*l]
+}
+
+func (l *level) Type() string {
+	return "level"
+}
+
+// named is a value of no interest but its type code.
+type named string
+
+func (n *named) Set(string) error {
+	return nil
+}
+
+func (n *named) String() string {
+	return ""
+}
+
+func (n *named) Type() string {
+	return string(*n)
+}
+
+func TestValueTypes(t *testing.T) {
+	aParser, err := NewParser("r:float:0.5 u:uint d:duration:1m t:time m:map f:[float]")
+	assertNoError(t, err)
+	assertNoError(t, aParser.ParseArgs([]string{"-u", "7", "-t", "2019-05-01T10:00:00Z", "-m", "a=1,b=x=y", "-f", "1.5,-2"}))
+
+	r, err := aParser.FloatValueOf("r")
+	assertNoError(t, err)
+	assertEqual(t, r, 0.5)
+	u, err := aParser.UintValueOf("u")
+	assertNoError(t, err)
+	assertEqual(t, u, uint(7))
+	d, err := aParser.DurationValueOf("d")
+	assertNoError(t, err)
+	assertEqual(t, d, time.Minute)
+	tm, err := aParser.TimeValueOf("t")
+	assertNoError(t, err)
+	assertEqual(t, tm, time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC))
+	m, err := aParser.MapValueOf("m")
+	assertNoError(t, err)
+	assertEqual(t, m, map[string]string{"a": "1", "b": "x=y"})
+	f, err := aParser.FloatListOf("f")
+	assertNoError(t, err)
+	assertEqual(t, f, []float64{1.5, -2})
+
+	assertNoError(t, aParser.ParseArgs([]string{"-u", "-1", "-d", "5", "-t", "today", "-m", "a", "-r", "x", "-f", "1,x"}))
+	_, err = aParser.UintValueOf("u")
+	assertError(t, err, strconv.ErrSyntax)
+	_, err = aParser.DurationValueOf("d")
+	if _, ok := err.(*ValueError); !ok {
+		t.Errorf("got %v, want a *ValueError", err)
+	}
+	_, err = aParser.TimeValueOf("t")
+	if _, ok := err.(*ValueError); !ok {
+		t.Errorf("got %v, want a *ValueError", err)
+	}
+	_, err = aParser.MapValueOf("m")
+	assertError(t, err, ErrMapEntry)
+	_, err = aParser.FloatValueOf("r")
+	assertError(t, err, strconv.ErrSyntax)
+	_, err = aParser.FloatListOf("f")
+	assertError(t, err, strconv.ErrSyntax)
+
+	t.Run("registry", func(t *testing.T) {
+		assertNoError(t, RegisterType(func() Value { return new(level) }))
+		assertError(t, RegisterType(func() Value { return new(level) }), ErrDuplicateType)
+		assertError(t, RegisterType(func() Value { n := named("int"); return &n }), ErrDuplicateType)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < cap(errs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- RegisterType(func() Value { n := named("racy"); return &n })
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		registered := 0
+		for err := range errs {
+			if err == nil {
+				registered++
+			}
+		}
+		assertEqual(t, registered, 1)
+
+		aParser, err := NewParser("v:level")
+		assertNoError(t, err)
+		assertNoError(t, aParser.Parse("-v warn"))
+		v, err := aParser.ValueOf("v")
+		assertNoError(t, err)
+		assertEqual(t, *v.(*level), level(2))
+
+		assertNoError(t, aParser.Parse(""))
+		v, err = aParser.ValueOf("v")
+		assertNoError(t, err)
+		assertEqual(t, v.String(), "debug")
+
+		assertNoError(t, aParser.Parse("-v trace"))
+		_, err = aParser.ValueOf("v")
+		assertStrings(t, cause(err).Error(), "unknown level")
+
+		sr, err := NewSchemaRule("v", "level", "info")
+		assertNoError(t, err)
+		aSchema, err := NewSchema(sr)
+		assertNoError(t, err)
+		v, err = NewParserFromSchema(aSchema).ValueOf("v")
+		assertNoError(t, err)
+		assertEqual(t, v.String(), "info")
+
+		var cfg struct {
+			Level   level             `arg:"v" default:"info"`
+			Timeout time.Duration     `arg:"timeout" default:"30s"`
+			Ratio   float64           `arg:"r"`
+			Labels  map[string]string `arg:"labels"`
+			Since   time.Time         `arg:"since"`
+		}
+		assertNoError(t, Bind(&cfg, []string{"--timeout=1s", "-r", "0.25", "--labels", "env=prod"}))
+		assertEqual(t, cfg.Level, level(1))
+		assertEqual(t, cfg.Timeout, time.Second)
+		assertEqual(t, cfg.Ratio, 0.25)
+		assertEqual(t, cfg.Labels, map[string]string{"env": "prod"})
+		assertEqual(t, cfg.Since, time.Time{})
+	})
+}
+
+func TestSources(t *testing.T) {
+	newRule := func(flag, typeCode, defaultValue string) *SchemaRule {
+		sr, err := NewSchemaRule(flag, typeCode, defaultValue)
+		assertNoError(t, err)
+		return sr
+	}
+	aSchema, err := NewSchema(
+		newRule("p", "int", "80").WithEnv("APP_PORT").WithKey("server.port"),
+		newRule("d", "string", "./logs").WithEnv("APP_DIR").WithKey("dir"),
+		newRule("h", "string", "localhost").WithKey("db.host"),
+		newRule("t", "[int]", "").WithKey("ports"),
+		newRule("u", "string", "").WithEnv("APP_USER").Required(),
+	)
+	assertNoError(t, err)
+	aParser := NewParserFromSchema(aSchema)
+	env := map[string]string{"APP_DIR": "/env/logs", "APP_USER": "root"}
+	aParser.SetLookupEnv(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+
+	config := `{"server": {"port": 8080}, "dir": "/file/logs", "ports": [1, 2], "unused": true}`
+	assertNoError(t, aParser.LoadConfig(strings.NewReader(config), "json"))
+	assertNoError(t, aParser.ParseArgs(nil))
+
+	sourceTests := []struct {
+		flag   string
+		value  string
+		source Source
+	}{
+		{"p", "8080", SourceFile},
+		{"d", "/env/logs", SourceEnv},
+		{"h", "localhost", SourceDefault},
+		{"t", "1,2", SourceFile},
+		{"u", "root", SourceEnv},
+	}
+	for _, tt := range sourceTests {
+		v, err := aParser.StringValueOf(tt.flag)
+		assertNoError(t, err)
+		assertStrings(t, v, tt.value)
+		source, err := aParser.SourceOf(tt.flag)
+		assertNoError(t, err)
+		assertStrings(t, source.String(), tt.source.String())
+	}
+
+	assertNoError(t, aParser.ParseArgs([]string{"-p", "9090", "-d", "/flag/logs"}))
+	p, err := aParser.IntValueOf("p")
+	assertNoError(t, err)
+	assertEqual(t, p, 9090)
+	source, err := aParser.SourceOf("d")
+	assertNoError(t, err)
+	assertEqual(t, source, SourceFlag)
+
+	delete(env, "APP_USER")
+	assertError(t, aParser.ParseArgs(nil), ErrRequired)
+	env["APP_USER"] = "root"
+
+	t.Run("validated after loading", func(t *testing.T) {
+		sr, err := NewSchemaRule("p", "int", "")
+		assertNoError(t, err)
+		sr.WithKey("port").WithRange(1, 65535).Required()
+		aSchema, err := NewSchema(sr)
+		assertNoError(t, err)
+		aParser := NewParserFromSchema(aSchema)
+
+		assertNoError(t, aParser.LoadConfig(strings.NewReader(`{"port": 80}`), "json"))
+		assertNoError(t, aParser.ParseArgs(nil))
+
+		assertError(t, aParser.LoadConfig(strings.NewReader(`{"port": 0}`), "json"), ErrOutOfRange)
+		assertError(t, aParser.LoadConfig(strings.NewReader(`{}`), "json"), ErrRequired)
+		assertNoError(t, aParser.LoadConfig(strings.NewReader(`{"port": 8080}`), "json"))
+	})
+
+	t.Run("ini", func(t *testing.T) {
+		ini := `; comment
+dir = "/ini/logs"
+[server]
+port=7070
+[db]
+# comment
+host = db.local
+`
+		assertNoError(t, aParser.LoadConfig(strings.NewReader(ini), "ini"))
+		for flag, want := range map[string]string{"p": "7070", "h": "db.local", "t": "[]"} {
+			v, err := aParser.StringValueOf(flag)
+			assertNoError(t, err)
+			assertStrings(t, v, want)
+		}
+
+		err := aParser.LoadConfig(strings.NewReader("[server]\nport\n"), "ini")
+		configErr, ok := err.(*ConfigError)
+		if !ok {
+			t.Fatalf("got %v, want a *ConfigError", err)
+		}
+		assertEqual(t, configErr.Line, 2)
+		assertError(t, aParser.LoadConfig(strings.NewReader(""), "yaml"), ErrUnknownConfigFormat)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "args2")
+		assertNoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "app.json")
+		assertNoError(t, ioutil.WriteFile(path, []byte(`{"db": {"host": "json.local"}}`), 0644))
+		assertNoError(t, aParser.LoadConfigFile(path))
+		h, err := aParser.StringValueOf("h")
+		assertNoError(t, err)
+		assertStrings(t, h, "json.local")
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	aParser, err := NewParser("l|log:bool p|port:int v|verbose:bool")
+	assertNoError(t, err)
+
+	t.Run("suggestion", func(t *testing.T) {
+		err := aParser.ParseArgs([]string{"-l", "--prot", "80"})
+		flagErr, ok := err.(*FlagError)
+		if !ok {
+			t.Fatalf("got %v, want a *FlagError", err)
+		}
+		assertEqual(t, flagErr.Index, 1)
+		assertStrings(t, flagErr.Token, "--prot")
+		assertStrings(t, flagErr.Flag, "prot")
+		assertStrings(t, flagErr.Suggestion, "--port")
+		assertStrings(t, flagErr.Error(), `argument 1 "--prot": flag --prot: flag not exist, did you mean --port?`)
+
+		suggestTests := []struct {
+			arguments []string
+			want      string
+		}{
+			{[]string{"--verbos"}, "--verbose"},
+			{[]string{"--lgo"}, "--log"},
+			{[]string{"-x"}, ""},
+			{[]string{"--timeout"}, ""},
+		}
+		for _, tt := range suggestTests {
+			err := aParser.ParseArgs(tt.arguments)
+			assertError(t, err, ErrorFlagNotExist)
+			assertStrings(t, err.(*FlagError).Suggestion, tt.want)
+		}
+
+		_, err = aParser.StringValueOf("prot")
+		assertStrings(t, err.Error(), "flag --prot: flag not exist, did you mean --port?")
+
+		err = aParser.ParseArgs([]string{"-prot"})
+		assertStrings(t, err.Error(), `argument 0 "-prot": flag -prot: flag not exist, did you mean --port?`)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		for _, arguments := range [][]string{{"-l", "-p"}, {"-l", "--port"}} {
+			err := aParser.ParseArgs(arguments)
+			flagErr, ok := err.(*FlagError)
+			if !ok {
+				t.Fatalf("got %v, want a *FlagError", err)
+			}
+			assertError(t, err, ErrMissingValue)
+			assertEqual(t, flagErr.Index, 1)
+			assertStrings(t, flagErr.TypeCode, "int")
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		assertNoError(t, aParser.ParseArgs([]string{"-l", "--port=http"}))
+		_, err := aParser.IntValueOf("port")
+		valueErr, ok := err.(*ValueError)
+		if !ok {
+			t.Fatalf("got %v, want a *ValueError", err)
+		}
+		assertEqual(t, valueErr.Index, 1)
+		assertStrings(t, valueErr.Token, "--port=http")
+		assertStrings(t, valueErr.Value, "http")
+		assertStrings(t, valueErr.Error(), `argument 1 "--port=http": flag --port: invalid int value "http": strconv.Atoi: parsing "http": invalid syntax`)
+
+		withDefault, err := NewParser("p:int:http")
+		assertNoError(t, err)
+		_, err = withDefault.IntValueOf("p")
+		assertEqual(t, err.(*ValueError).Index, -1)
+	})
+}
+
+func TestBoolSemantics(t *testing.T) {
+	aParser, err := NewParser("l|log:bool:true x:bool v|verbose:count p:int")
+	assertNoError(t, err)
+
+	tests := []struct {
+		arguments   []string
+		log, x      bool
+		verbose     int
+		positionals []string
+	}{
+		{[]string{"-l", "false"}, false, false, 0, nil},
+		{[]string{"--log", "no", "-x", "YES"}, false, true, 0, nil},
+		{[]string{"-l=false", "-x=1"}, false, true, 0, nil},
+		{[]string{"--no-log", "-x", "0"}, false, false, 0, nil},
+		{[]string{"-x", "-p", "1"}, true, true, 0, nil},
+		{[]string{"-vvv"}, true, false, 3, nil},
+		{[]string{"-v", "--verbose", "-xv"}, true, true, 3, nil},
+		{[]string{"--verbose=5"}, true, false, 5, nil},
+		{[]string{"-x", "file"}, true, true, 0, []string{"file"}},
+	}
+	for _, tt := range tests {
+		if len(tt.positionals) > 0 {
+			withFile, err := NewParser("l|log:bool:true x:bool v|verbose:count p:int [file]")
+			assertNoError(t, err)
+			assertNoError(t, withFile.ParseArgs(tt.arguments))
+			assertEqual(t, withFile.Args(), tt.positionals)
+			continue
+		}
+		assertNoError(t, aParser.ParseArgs(tt.arguments))
+		l, err := aParser.BoolValueOf("l")
+		assertNoError(t, err)
+		x, err := aParser.BoolValueOf("x")
+		assertNoError(t, err)
+		v, err := aParser.CountValueOf("v")
+		assertNoError(t, err)
+		if l != tt.log || x != tt.x || v != tt.verbose {
+			t.Errorf("%v: got %t %t %d, want %t %t %d", tt.arguments, l, x, v, tt.log, tt.x, tt.verbose)
+		}
+	}
+
+	for _, arguments := range [][]string{{"-l=maybe"}, {"-v", "--l=maybe"}, {"-xl=maybe"}} {
+		err = aParser.ParseArgs(arguments)
+		valueErr, ok := err.(*ValueError)
+		if !ok {
+			t.Fatalf("%v got %v, want a *ValueError", arguments, err)
+		}
+		assertEqual(t, valueErr.Index, len(arguments)-1)
+		assertStrings(t, valueErr.Value, "maybe")
+		assertError(t, err, strconv.ErrSyntax)
+	}
+
+	countTests := []struct {
+		arguments []string
+		value     string
+		want      error
+	}{
+		{[]string{"-v=abc"}, "abc", strconv.ErrSyntax},
+		{[]string{"-l", "--verbose=-1"}, "-1", strconv.ErrRange},
+	}
+	for _, tt := range countTests {
+		err = aParser.ParseArgs(tt.arguments)
+		valueErr, ok := err.(*ValueError)
+		if !ok {
+			t.Fatalf("%v got %v, want a *ValueError", tt.arguments, err)
+		}
+		assertEqual(t, valueErr.Index, len(tt.arguments)-1)
+		assertStrings(t, valueErr.TypeCode, "count")
+		assertStrings(t, valueErr.Value, tt.value)
+		assertError(t, err, tt.want)
+	}
+
+	assertError(t, aParser.ParseArgs([]string{"--no-p"}), ErrorFlagNotExist)
+
+	var cfg struct {
+		Verbose int  `arg:"v" count:"true"`
+		Color   bool `arg:"color" default:"true"`
+	}
+	assertNoError(t, Bind(&cfg, []string{"-vv", "--no-color"}))
+	assertEqual(t, cfg.Verbose, 2)
+	assertEqual(t, cfg.Color, false)
+}
+
+func TestLists(t *testing.T) {
+	paths, err := NewSchemaRule("P", "[string]", "")
+	assertNoError(t, err)
+	aSchema, err := NewSchema(paths.WithSeparator(":"))
+	assertNoError(t, err)
+	d, err := newSchemaRule("d:[string]")
+	assertNoError(t, err)
+	g, err := newSchemaRule("g:[int]:1,2")
+	assertNoError(t, err)
+	m, err := newSchemaRule("m:map")
+	assertNoError(t, err)
+	for _, sr := range []*SchemaRule{d, g, m} {
+		assertNoError(t, aSchema.addSchemaRule(sr))
+	}
+	aParser := NewParserFromSchema(aSchema)
+
+	listTests := []struct {
+		arguments []string
+		flag      string
+		want      []string
+	}{
+		{[]string{"-d", "a", "-d", "b,c"}, "d", []string{"a", "b", "c"}},
+		{[]string{"-d", `a\,b`, "-d", `c\\`}, "d", []string{"a,b", `c\`}},
+		{[]string{"-d", "[]"}, "d", nil},
+		{[]string{"-d", "a", "-d", "[]", "-d", "b"}, "d", []string{"b"}},
+		{[]string{"-d", `\[]`}, "d", []string{"[]"}},
+		{nil, "d", nil},
+		{[]string{"-P", "/bin:/usr/bin", "-P", "/a,b"}, "P", []string{"/bin", "/usr/bin", "/a,b"}},
+	}
+	for _, tt := range listTests {
+		assertNoError(t, aParser.ParseArgs(tt.arguments))
+		got, err := aParser.StringListOf(tt.flag)
+		assertNoError(t, err)
+		assertEqual(t, got, tt.want)
+	}
+
+	assertNoError(t, aParser.ParseArgs([]string{"-g", "3", "-g", "4,5", "-m", "a=1", "-m", `b=x\,y`}))
+	ints, err := aParser.IntListOf("g")
+	assertNoError(t, err)
+	assertEqual(t, ints, []int{3, 4, 5})
+	entries, err := aParser.MapValueOf("m")
+	assertNoError(t, err)
+	assertEqual(t, entries, map[string]string{"a": "1", "b": "x,y"})
+
+	assertNoError(t, aParser.ParseArgs([]string{"-g", "[]"}))
+	ints, err = aParser.IntListOf("g")
+	assertNoError(t, err)
+	assertEqual(t, len(ints), 0)
+
+	t.Run("config", func(t *testing.T) {
+		d.WithKey("dirs")
+		config := `{"dirs": ["a,b", "c"]}`
+		assertNoError(t, aParser.LoadConfig(strings.NewReader(config), "json"))
+		assertNoError(t, aParser.ParseArgs(nil))
+		got, err := aParser.StringListOf("d")
+		assertNoError(t, err)
+		assertEqual(t, got, []string{"a,b", "c"})
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		var cfg struct {
+			Tags  []string `arg:"t" sep:";"`
+			Ports []int    `arg:"p" default:"[]"`
+		}
+		assertNoError(t, Bind(&cfg, []string{"-t", "a,b;c", "-t", "d"}))
+		assertEqual(t, cfg.Tags, []string{"a,b", "c", "d"})
+		assertEqual(t, len(cfg.Ports), 0)
+	})
+}
+
+func TestSchemaGrammar(t *testing.T) {
+	aSchema, err := newSchema("\tu:string:http://localhost:8080  d:string:\"/my logs\"\n" +
+		`s:string:'a "quoted" value' e:string:a\ b\:c p|port=PORT:int:80 'port: "to listen on"'` + "\n")
+	assertNoError(t, err)
+
+	defaultTests := []struct {
+		flag string
+		want string
+	}{
+		{"u", "http://localhost:8080"},
+		{"d", "/my logs"},
+		{"s", `a "quoted" value`},
+		{"e", "a b:c"},
+		{"port", "80"},
+	}
+	for _, tt := range defaultTests {
+		v, err := aSchema.defaultValueOf(tt.flag)
+		assertNoError(t, err)
+		assertStrings(t, v, tt.want)
+	}
+	port, err := aSchema.schemaRuleOf("p")
+	assertNoError(t, err)
+	assertStrings(t, port.getDescription(), `port: "to listen on"`)
+	assertStrings(t, port.getPlaceholder(), "PORT")
+
+	sr, err := newSchemaRule("u:string:http://localhost:8080")
+	assertNoError(t, err)
+	assertStrings(t, sr.getDefaultValue(), "http://localhost:8080")
+
+	empty, err := newSchema(" \n\t")
+	assertNoError(t, err)
+	assertEqual(t, empty.size(), 0)
+
+	errorTests := []struct {
+		schema string
+		err    error
+		column int
+	}{
+		{"l:bool p", ErrWrongSchemaRule, 9},
+		{"l:bool  p:complex", ErrNotSupportArgumentType, 11},
+		{"l:bool |p:int", ErrWrongSchemaRule, 8},
+		{`l:bool d:string:"/my logs`, ErrUnterminatedQuote, 17},
+		{`l:bool d:string:\`, ErrTrailingEscape, 17},
+		{`"no rule" l:bool`, ErrWrongSchemaRule, 1},
+		{`l:bool "a" "b"`, ErrWrongSchemaRule, 12},
+		{"l:bool p|l:int", ErrDuplicateFlag, 8},
+		{"[a] <b>", ErrWrongPositional, 5},
+		{"<a>:x", ErrWrongPositional, 5},
+		{"l:bool @", ErrWrongSchemaRule, 8},
+		{"l:bool é:complex", ErrNotSupportArgumentType, 10},
+	}
+	for _, tt := range errorTests {
+		_, err := newSchema(tt.schema)
+		schemaErr, ok := err.(*SchemaError)
+		if !ok {
+			t.Errorf("%q: got %v, want a *SchemaError", tt.schema, err)
+			continue
+		}
+		assertError(t, err, tt.err)
+		if schemaErr.Column != tt.column {
+			t.Errorf("%q: got column %d, want %d", tt.schema, schemaErr.Column, tt.column)
+		}
+	}
+
+	_, err = NewParser("l:bool  p:complex")
+	assertStrings(t, err.Error(), `schema column 11: rule "p:complex": not support argument type`)
+
+	t.Run("list defaults", func(t *testing.T) {
+		aParser, err := NewParser(`d:[string]:a\,b,c t:[string]:"x y",z\,w\:v e:string:a\b\\c`)
+		assertNoError(t, err)
+		assertNoError(t, aParser.Parse(""))
+
+		listTests := []struct {
+			flag string
+			want []string
+		}{
+			{"d", []string{"a,b", "c"}},
+			{"t", []string{"x y", "z,w:v"}},
+		}
+		for _, tt := range listTests {
+			got, err := aParser.StringListOf(tt.flag)
+			assertNoError(t, err)
+			assertEqual(t, got, tt.want)
+		}
+		e, err := aParser.StringValueOf("e")
+		assertNoError(t, err)
+		assertStrings(t, e, `a\b\c`)
+	})
+}
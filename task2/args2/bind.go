@@ -0,0 +1,273 @@
+package args2
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotStructPointer = errors.New("not a pointer to a struct")
+
+var typeCodes = map[reflect.Type]string{
+	reflect.TypeOf(false):               "bool",
+	reflect.TypeOf(0):                   "int",
+	reflect.TypeOf(""):                  "string",
+	reflect.TypeOf([]string{}):          "[string]",
+	reflect.TypeOf([]int{}):             "[int]",
+	reflect.TypeOf(0.0):                 "float",
+	reflect.TypeOf(uint(0)):             "uint",
+	reflect.TypeOf(time.Duration(0)):    "duration",
+	reflect.TypeOf(time.Time{}):         "time",
+	reflect.TypeOf(map[string]string{}): "map",
+	reflect.TypeOf([]float64{}):         "[float]",
+}
+
+// binding ties a flag or positional parameter to the struct field it
+// fills.
+type binding struct {
+	name       string
+	positional bool
+	field      reflect.Value
+}
+
+// Bind parses argumentsData against the schema described by the tags of
+// the struct v points to and fills its fields, see SchemaOf for the tags.
+func Bind(v interface{}, argumentsData []string) error {
+	aSchema, bindings, err := schemaOf(v)
+	if err != nil {
+		return err
+	}
+	aParser := NewParserFromSchema(aSchema)
+	if err := aParser.ParseArgs(argumentsData); err != nil {
+		return err
+	}
+	return aParser.fill(bindings)
+}
+
+// SchemaOf derives a schema from the tags of the struct v points to:
+//
+//	Port  int      `arg:"p,port" default:"80" help:"port to listen on"`
+//	Files []string `arg:"<files>..."`
+//
+// arg lists the flag and its aliases or declares a positional parameter,
+// fields without it are ignored. Fields of a nested struct are flags too,
+// prefixed with "name-" when the struct field has an arg tag. Fields whose
+// pointer implements Value hold a type registered with RegisterType and
+// count:"true" makes an int a count flag and sep sets the separator of a
+// list.
+//
+// help, placeholder and group set how help output shows the field, env
+// binds it to an environment variable and required, enum, min, max and
+// pattern add constraints.
+func SchemaOf(v interface{}) (*Schema, error) {
+	aSchema, _, err := schemaOf(v)
+	return aSchema, err
+}
+
+func schemaOf(v interface{}) (*Schema, []binding, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, nil, ErrNotStructPointer
+	}
+
+	aSchema, _ := NewSchema()
+	var bindings []binding
+	var walk func(rv reflect.Value, prefix string) error
+	walk = func(rv reflect.Value, prefix string) error {
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			tag, tagged := sf.Tag.Lookup("arg")
+			if sf.PkgPath != "" || tag == "-" {
+				continue
+			}
+			value, isValue := rv.Field(i).Addr().Interface().(Value)
+			if sf.Type.Kind() == reflect.Struct && !isValue && sf.Type != reflect.TypeOf(time.Time{}) {
+				nestedPrefix := prefix
+				if tagged {
+					nestedPrefix = prefix + tag + "-"
+				}
+				if err := walk(rv.Field(i), nestedPrefix); err != nil {
+					return err
+				}
+				continue
+			}
+			if !tagged {
+				continue
+			}
+
+			typeCode, ok := typeCodes[sf.Type]
+			if isValue {
+				typeCode, ok = value.Type(), true
+			}
+			if typeCode == "int" && sf.Tag.Get("count") == "true" {
+				typeCode = "count"
+			}
+			if !ok {
+				return &SchemaError{Rule: sf.Name, Err: ErrNotSupportArgumentType}
+			}
+
+			if isPositionalString(tag) {
+				ps, err := newPositional(tag)
+				if err != nil {
+					return err
+				}
+				want := "string"
+				if ps.variadic {
+					want = "[string]"
+				}
+				if typeCode != want {
+					return &SchemaError{Rule: sf.Name, Err: ErrNotSupportArgumentType}
+				}
+				if _, err := aSchema.WithPositionals(ps.WithDescription(sf.Tag.Get("help"))); err != nil {
+					return err
+				}
+				bindings = append(bindings, binding{ps.name, true, rv.Field(i)})
+				continue
+			}
+
+			names := strings.Split(tag, ",")
+			for j := range names {
+				names[j] = prefix + names[j]
+			}
+			sr, err := NewSchemaRule(names[0], typeCode, sf.Tag.Get("default"))
+			if err != nil {
+				return err
+			}
+			sr.WithAliases(names[1:]...).
+				WithDescription(sf.Tag.Get("help")).
+				WithPlaceholder(sf.Tag.Get("placeholder")).
+				WithGroup(sf.Tag.Get("group")).
+				WithEnv(sf.Tag.Get("env")).
+				WithSeparator(sf.Tag.Get("sep"))
+			if err := constrain(sr, sf.Tag); err != nil {
+				return &SchemaError{Rule: sf.Name, Err: err}
+			}
+			if err := aSchema.addSchemaRule(sr); err != nil {
+				return err
+			}
+			bindings = append(bindings, binding{names[0], false, rv.Field(i)})
+		}
+		return nil
+	}
+
+	if err := walk(rv.Elem(), ""); err != nil {
+		return nil, nil, err
+	}
+	return aSchema, bindings, nil
+}
+
+// constrain adds the constraints of the required, enum, min, max and
+// pattern tags.
+func constrain(sr *SchemaRule, tag reflect.StructTag) error {
+	if tag.Get("required") == "true" {
+		sr.Required()
+	}
+	if enum := tag.Get("enum"); enum != "" {
+		sr.WithEnum(strings.Split(enum, ",")...)
+	}
+
+	min, max := math.Inf(-1), math.Inf(1)
+	minTag, hasMin := tag.Lookup("min")
+	maxTag, hasMax := tag.Lookup("max")
+	var err error
+	if hasMin {
+		if min, err = strconv.ParseFloat(minTag, 64); err != nil {
+			return err
+		}
+	}
+	if hasMax {
+		if max, err = strconv.ParseFloat(maxTag, 64); err != nil {
+			return err
+		}
+	}
+	if hasMin || hasMax {
+		sr.WithRange(min, max)
+	}
+
+	if pattern, ok := tag.Lookup("pattern"); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		sr.WithPattern(re)
+	}
+	return nil
+}
+
+func (p *Parser) fill(bindings []binding) error {
+	for _, b := range bindings {
+		var value interface{}
+		var err error
+		switch {
+		case b.positional && b.field.Kind() == reflect.Slice:
+			value, err = p.PositionalsOf(b.name)
+		case b.positional:
+			value, err = p.PositionalOf(b.name)
+		case b.field.Addr().Type().Implements(valueType):
+			err = p.setValue(b.name, b.field.Addr().Interface().(Value))
+		default:
+			value, err = p.valueOf(b.name)
+		}
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			b.field.Set(reflect.ValueOf(value))
+		}
+	}
+	return nil
+}
+
+// valueOf returns the value of flag as its type.
+func (p *Parser) valueOf(flag string) (interface{}, error) {
+	typeCode, err := p.schema.typeOf(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeCode {
+	case "bool":
+		return p.BoolValueOf(flag)
+	case "int":
+		return p.IntValueOf(flag)
+	case "count":
+		return p.CountValueOf(flag)
+	case "[string]":
+		return p.StringListOf(flag)
+	case "[int]":
+		return p.IntListOf(flag)
+	case "float":
+		return p.FloatValueOf(flag)
+	case "uint":
+		return p.UintValueOf(flag)
+	case "duration":
+		return p.DurationValueOf(flag)
+	case "time":
+		return p.TimeValueOf(flag)
+	case "map":
+		return p.MapValueOf(flag)
+	case "[float]":
+		return p.FloatListOf(flag)
+	default:
+		return p.StringValueOf(flag)
+	}
+}
+
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+
+// setValue sets a struct field implementing Value from the flag.
+func (p *Parser) setValue(flag string, value Value) error {
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return err
+	}
+	if err := value.Set(v); err != nil {
+		return p.valueError(flag, value.Type(), v, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package args2
+
+// Command is a named parser with nested subcommands, such as "build" in
+// "tool build -v file1 file2".
+type Command struct {
+	name        string
+	description string
+	parser      *Parser
+	parent      *Command
+	commands    []*Command
+}
+
+// NewCommand creates a command parsing its own flags and positional
+// arguments with aSchema.
+func NewCommand(name, description string, aSchema *Schema) *Command {
+	c := new(Command)
+	c.name = name
+	c.description = description
+	c.parser = NewParserFromSchema(aSchema)
+	return c
+}
+
+// AddCommand adds subcommands, their names must be unique.
+func (c *Command) AddCommand(commands ...*Command) error {
+	for _, sub := range commands {
+		if c.commandOf(sub.name) != nil {
+			return &SchemaError{Rule: sub.name, Err: ErrDuplicateFlag}
+		}
+		sub.parent = c
+		c.commands = append(c.commands, sub)
+	}
+	return nil
+}
+
+func (c *Command) commandOf(name string) *Command {
+	for _, sub := range c.commands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the command.
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Path returns the names from the root command down to c, such as
+// "tool build".
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.name
+	}
+	return c.parent.Path() + " " + c.name
+}
+
+// Parser returns the parser holding the values of the command's flags and
+// positional arguments.
+func (c *Command) Parser() *Parser {
+	return c.parser
+}
+
+// ParseArgs parses the command's own flags up to the first positional
+// argument naming a subcommand, then hands the rest to that subcommand.
+// It returns the innermost command given, c itself when no subcommand is.
+func (c *Command) ParseArgs(argumentsData []string) (*Command, error) {
+	var isCommand func(string) bool
+	if len(c.commands) > 0 {
+		isCommand = func(name string) bool { return c.commandOf(name) != nil }
+	}
+	i, err := c.parser.parseArgs(argumentsData, isCommand)
+	if err != nil {
+		return c, err
+	}
+	if i == len(argumentsData) {
+		return c, nil
+	}
+	return c.commandOf(argumentsData[i]).ParseArgs(argumentsData[i+1:])
+}
+
+// Parse is ParseArgs for an arguments string, see SplitArguments.
+func (c *Command) Parse(aArgumentsString string) (*Command, error) {
+	argumentsData, err := SplitArguments(aArgumentsString)
+	if err != nil {
+		return c, err
+	}
+	return c.ParseArgs(argumentsData)
+}
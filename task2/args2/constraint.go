@@ -0,0 +1,236 @@
+package args2
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrRequired = errors.New("flag is required")
+var ErrOutOfRange = errors.New("value out of range")
+var ErrNotAllowed = errors.New("value not allowed")
+var ErrPatternMismatch = errors.New("value doesn't match pattern")
+var ErrListLength = errors.New("wrong number of values")
+var ErrExclusive = errors.New("flags are mutually exclusive")
+var ErrRequires = errors.New("flag requires another flag")
+
+// ConstraintError reports a constraint a flag violates.
+type ConstraintError struct {
+	Flag   string
+	Value  string
+	Err    error
+	Detail string
+}
+
+func (e *ConstraintError) Error() string {
+	flag := typedFlag(e.Flag, "")
+	msg := fmt.Sprintf("flag %s: %v", flag, e.Err)
+	if e.Value != "" {
+		msg = fmt.Sprintf("flag %s: %q: %v", flag, e.Value, e.Err)
+	}
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every constraint the arguments violate, in the
+// order the flags are defined.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the first violation.
+func (e *ValidationError) Unwrap() error {
+	return e.Errors[0]
+}
+
+type constraints struct {
+	required  bool
+	min, max  float64
+	hasRange  bool
+	enum      []string
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+	hasLength bool
+	requires  []string
+}
+
+// Required makes the flag fail validation when it isn't given.
+func (sr *SchemaRule) Required() *SchemaRule {
+	sr.constraints.required = true
+	return sr
+}
+
+// WithRange bounds a number, or every number of a list, to [min, max].
+// Use math.Inf for an open bound.
+func (sr *SchemaRule) WithRange(min, max float64) *SchemaRule {
+	sr.constraints.min, sr.constraints.max, sr.constraints.hasRange = min, max, true
+	return sr
+}
+
+// WithEnum allows only the given values, or list elements.
+func (sr *SchemaRule) WithEnum(values ...string) *SchemaRule {
+	sr.constraints.enum = values
+	return sr
+}
+
+// WithPattern requires the value, or every list element, to match re.
+func (sr *SchemaRule) WithPattern(re *regexp.Regexp) *SchemaRule {
+	sr.constraints.pattern = re
+	return sr
+}
+
+// WithLength bounds the number of elements of a list, a negative max
+// leaves it unbounded.
+func (sr *SchemaRule) WithLength(min, max int) *SchemaRule {
+	sr.constraints.minLength, sr.constraints.maxLength, sr.constraints.hasLength = min, max, true
+	return sr
+}
+
+// Requires makes the flag fail validation when it is given without all of
+// flags.
+func (sr *SchemaRule) Requires(flags ...string) *SchemaRule {
+	sr.constraints.requires = append(sr.constraints.requires, flags...)
+	return sr
+}
+
+// WithExclusive adds a group of flags of which at most one can be given.
+func (s *Schema) WithExclusive(flags ...string) (*Schema, error) {
+	group := make([]string, len(flags))
+	for i, flag := range flags {
+		sr, err := s.schemaRuleOf(flag)
+		if err != nil {
+			return nil, err
+		}
+		group[i] = sr.getFlag()
+	}
+	s.exclusive = append(s.exclusive, group)
+	return s, nil
+}
+
+func (p *Parser) isGiven(sr *SchemaRule) bool {
+	_, source := p.resolve(sr)
+	return source != SourceDefault
+}
+
+// validate checks the constraints of every flag and reports all
+// violations together.
+func (p *Parser) validate() error {
+	var errs []error
+	for _, flag := range p.schema.flags {
+		sr := p.schema.schemaRules[flag]
+		errs = append(errs, p.validateSchemaRule(sr)...)
+	}
+
+	for _, group := range p.schema.exclusive {
+		var given []string
+		for _, flag := range group {
+			if p.isGiven(p.schema.schemaRules[flag]) {
+				given = append(given, flag)
+			}
+		}
+		if len(given) > 1 {
+			others := make([]string, len(given)-1)
+			for i, flag := range given[1:] {
+				others[i] = typedFlag(flag, "")
+			}
+			errs = append(errs, &ConstraintError{given[0], "", ErrExclusive, "given with " + strings.Join(others, ", ")})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{errs}
+	}
+	return nil
+}
+
+func (p *Parser) validateSchemaRule(sr *SchemaRule) []error {
+	c := sr.constraints
+	flag := sr.getFlag()
+	if !p.isGiven(sr) {
+		if c.required {
+			return []error{&ConstraintError{flag, "", ErrRequired, ""}}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, other := range c.requires {
+		if osr, err := p.schema.schemaRuleOf(other); err != nil || !p.isGiven(osr) {
+			errs = append(errs, &ConstraintError{flag, "", ErrRequires, "needs " + typedFlag(other, "")})
+		}
+	}
+
+	value, _ := p.resolve(sr)
+	values := []string{value}
+	if strings.HasPrefix(sr.getTypeCode(), "[") {
+		values = splitList(value, sr.getSeparator())
+		n := len(values)
+		if c.hasLength && (n < c.minLength || (c.maxLength >= 0 && n > c.maxLength)) {
+			errs = append(errs, &ConstraintError{flag, value, ErrListLength, lengthDetail(c.minLength, c.maxLength)})
+		}
+	}
+
+	for _, v := range values {
+		if c.hasRange {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, p.valueError(flag, sr.getTypeCode(), v, err))
+			} else if n < c.min || n > c.max {
+				errs = append(errs, &ConstraintError{flag, v, ErrOutOfRange, rangeDetail(c.min, c.max)})
+			}
+		}
+		if len(c.enum) > 0 && !contains(c.enum, v) {
+			errs = append(errs, &ConstraintError{flag, v, ErrNotAllowed, "one of " + strings.Join(c.enum, ", ")})
+		}
+		if c.pattern != nil && !c.pattern.MatchString(v) {
+			errs = append(errs, &ConstraintError{flag, v, ErrPatternMismatch, c.pattern.String()})
+		}
+	}
+	return errs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func rangeDetail(min, max float64) string {
+	switch {
+	case math.IsInf(min, -1):
+		return fmt.Sprintf("at most %g", max)
+	case math.IsInf(max, 1):
+		return fmt.Sprintf("at least %g", min)
+	default:
+		return fmt.Sprintf("between %g and %g", min, max)
+	}
+}
+
+func lengthDetail(min, max int) string {
+	if max < 0 {
+		return fmt.Sprintf("at least %d", min)
+	}
+	return fmt.Sprintf("between %d and %d", min, max)
+}
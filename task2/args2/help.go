@@ -0,0 +1,209 @@
+package args2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// helpEntry is a line of help output: a command, a positional parameter
+// or a flag with its names.
+type helpEntry struct {
+	names       []string
+	placeholder string
+	description string
+}
+
+type helpSection struct {
+	title   string
+	entries []helpEntry
+}
+
+// usage writes how the command is called, such as
+// "tool build [flags] <files>...".
+func (c *Command) usage() string {
+	aSchema := c.parser.schema
+	usage := []string{c.Path()}
+	if len(aSchema.flags) > 0 {
+		usage = append(usage, "[flags]")
+	}
+	if len(c.commands) > 0 {
+		usage = append(usage, "<command>")
+	}
+	for _, ps := range aSchema.positionals {
+		usage = append(usage, ps.String())
+	}
+	return strings.Join(usage, " ")
+}
+
+// sections lists the subcommands, the positional parameters, the flags
+// without a group and then every group in the order it first appears.
+func (c *Command) sections() []helpSection {
+	var sections []helpSection
+	aSchema := c.parser.schema
+
+	if len(c.commands) > 0 {
+		section := helpSection{title: "Commands"}
+		for _, sub := range c.commands {
+			section.entries = append(section.entries, helpEntry{[]string{sub.name}, "", sub.description})
+		}
+		sections = append(sections, section)
+	}
+
+	if len(aSchema.positionals) > 0 {
+		section := helpSection{title: "Arguments"}
+		for _, ps := range aSchema.positionals {
+			section.entries = append(section.entries, helpEntry{[]string{ps.String()}, "", ps.description})
+		}
+		sections = append(sections, section)
+	}
+
+	groups := []string{""}
+	flagsOf := map[string][]helpEntry{}
+	for _, flag := range aSchema.flags {
+		sr := aSchema.schemaRules[flag]
+		if _, ok := flagsOf[sr.getGroup()]; !ok && sr.getGroup() != "" {
+			groups = append(groups, sr.getGroup())
+		}
+		flagsOf[sr.getGroup()] = append(flagsOf[sr.getGroup()], flagEntry(sr))
+	}
+	for _, group := range groups {
+		if len(flagsOf[group]) == 0 {
+			continue
+		}
+		title := group
+		if title == "" {
+			title = "Flags"
+		}
+		sections = append(sections, helpSection{title, flagsOf[group]})
+	}
+	return sections
+}
+
+func flagEntry(sr *SchemaRule) helpEntry {
+	var names []string
+	for _, name := range sr.names() {
+		if len(name) == 1 {
+			names = append(names, "-"+name)
+		} else {
+			names = append(names, "--"+name)
+		}
+	}
+
+	description := sr.getDescription()
+	if enum := sr.constraints.enum; len(enum) > 0 {
+		description += " (one of " + strings.Join(enum, ", ") + ")"
+	}
+	if sr.env != "" {
+		description += " (env " + sr.env + ")"
+	}
+	if sr.constraints.required {
+		description += " (required)"
+	} else if sr.getDefaultValue() != getDefaultValue(sr.getTypeCode()) {
+		description += " (default " + sr.getDefaultValue() + ")"
+	}
+	description = strings.TrimSpace(description)
+	return helpEntry{names, sr.getPlaceholder(), description}
+}
+
+func (e helpEntry) term() string {
+	term := strings.Join(e.names, ", ")
+	if e.placeholder != "" {
+		term += " " + e.placeholder
+	}
+	return term
+}
+
+// Help writes the usage of the command and its subcommands, positional
+// parameters and flags in aligned columns.
+func (c *Command) Help() string {
+	var sb strings.Builder
+	sb.WriteString("Usage: " + c.usage() + "\n")
+	if c.description != "" {
+		sb.WriteString("\n" + c.description + "\n")
+	}
+
+	sections := c.sections()
+	width := 0
+	for _, section := range sections {
+		for _, e := range section.entries {
+			if len(e.term()) > width {
+				width = len(e.term())
+			}
+		}
+	}
+	for _, section := range sections {
+		sb.WriteString("\n" + section.title + ":\n")
+		for _, e := range section.entries {
+			line := fmt.Sprintf("  %-*s  %s", width, e.term(), e.description)
+			sb.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Markdown writes the help of the command as a Markdown reference.
+func (c *Command) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# " + c.Path() + "\n\n")
+	if c.description != "" {
+		sb.WriteString(c.description + "\n\n")
+	}
+	sb.WriteString("## Usage\n\n```\n" + c.usage() + "\n```\n")
+
+	for _, section := range c.sections() {
+		sb.WriteString("\n## " + section.title + "\n\n")
+		sb.WriteString("| Name | Description |\n| --- | --- |\n")
+		for _, e := range section.entries {
+			description := strings.Replace(e.description, "|", `\|`, -1)
+			fmt.Fprintf(&sb, "| `%s` | %s |\n", e.term(), description)
+		}
+	}
+	return sb.String()
+}
+
+// ManPage writes the help of the command as a man page in roff.
+func (c *Command) ManPage() string {
+	var sb strings.Builder
+	title := strings.ToUpper(strings.Replace(c.Path(), " ", "-", -1))
+	fmt.Fprintf(&sb, ".TH %s 1\n", roffEscape(title))
+	sb.WriteString(".SH NAME\n" + roffEscape(c.Path()))
+	if c.description != "" {
+		sb.WriteString(` \- ` + roffEscape(c.description))
+	}
+	sb.WriteString("\n.SH SYNOPSIS\n")
+	fmt.Fprintf(&sb, "\\fB%s\\fR%s\n", roffEscape(c.Path()), roffEscape(strings.TrimPrefix(c.usage(), c.Path())))
+
+	for _, section := range c.sections() {
+		sb.WriteString(".SH " + roffEscape(strings.ToUpper(section.title)) + "\n")
+		for _, e := range section.entries {
+			names := make([]string, len(e.names))
+			for i, name := range e.names {
+				names[i] = `\fB` + roffEscape(name) + `\fR`
+			}
+			term := strings.Join(names, ", ")
+			if e.placeholder != "" {
+				term += ` \fI` + roffEscape(e.placeholder) + `\fR`
+			}
+			sb.WriteString(".TP\n" + term + "\n")
+			if e.description != "" {
+				sb.WriteString(roffLine(e.description) + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func roffEscape(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	return strings.Replace(s, "-", `\-`, -1)
+}
+
+// roffLine escapes a line of text, guarding a leading "." or "'" that
+// roff would read as a request.
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package args2
+
+import "strings"
+
+// emptyList is the value of a list with no elements, such as the default
+// of a list flag or "-d []".
+const emptyList = "[]"
+
+func isListType(typeCode string) bool {
+	return strings.HasPrefix(typeCode, "[") || typeCode == "map"
+}
+
+// WithSeparator sets the separator between the elements of a list or map
+// value, "," by default.
+func (sr *SchemaRule) WithSeparator(separator string) *SchemaRule {
+	sr.separator = separator
+	return sr
+}
+
+func (sr *SchemaRule) getSeparator() string {
+	if sr.separator == "" {
+		return ","
+	}
+	return sr.separator
+}
+
+// splitList splits a list value at every separator that isn't escaped by
+// a backslash, "[]" is the empty list.
+func splitList(value, separator string) []string {
+	if value == emptyList {
+		return nil
+	}
+
+	var result []string
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			i++
+			sb.WriteByte(value[i])
+		case strings.HasPrefix(value[i:], separator):
+			result = append(result, sb.String())
+			sb.Reset()
+			i += len(separator) - 1
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+	return append(result, sb.String())
+}
+
+// escapeList escapes the backslashes and separators of an element.
+func escapeList(element, separator string) string {
+	element = strings.Replace(element, `\`, `\\`, -1)
+	return strings.Replace(element, separator, `\`+separator, -1)
+}
+
+// appendList adds the elements of value to the list given before, "[]"
+// empties the list.
+func appendList(list, value, separator string) string {
+	if list == emptyList || value == emptyList {
+		return value
+	}
+	return list + separator + value
+}
+
+// listOf returns the elements of a list flag and its raw value.
+func (p *Parser) listOf(flag string) ([]string, string, error) {
+	sr, err := p.schema.schemaRuleOf(flag)
+	if err != nil {
+		return nil, "", err
+	}
+	v, _ := p.resolve(sr)
+	return splitList(v, sr.getSeparator()), v, nil
+}
@@ -0,0 +1,172 @@
+package args2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrWrongPositional = errors.New("wrong positional parameter")
+var ErrMissingArgument = errors.New("missing argument")
+var ErrUnexpectedArgument = errors.New("unexpected argument")
+var ErrPositionalNotExist = errors.New("positional parameter not exist")
+
+// ArgumentError reports a positional argument that is missing or isn't
+// expected.
+type ArgumentError struct {
+	Name string
+	Err  error
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("argument %s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// Positional is a parameter given by its position instead of a flag.
+type Positional struct {
+	name        string
+	optional    bool
+	variadic    bool
+	description string
+}
+
+// NewPositional creates a required positional parameter.
+func NewPositional(name string) *Positional {
+	return &Positional{name: name}
+}
+
+// Optional makes the parameter optional.
+func (ps *Positional) Optional() *Positional {
+	ps.optional = true
+	return ps
+}
+
+// Variadic makes the parameter take every remaining argument, it must be
+// the last one.
+func (ps *Positional) Variadic() *Positional {
+	ps.variadic = true
+	return ps
+}
+
+// WithDescription sets the text help output describes the parameter with.
+func (ps *Positional) WithDescription(description string) *Positional {
+	ps.description = description
+	return ps
+}
+
+// String writes the parameter the way the schema string declares it.
+func (ps *Positional) String() string {
+	s := "<" + ps.name + ">"
+	if ps.optional {
+		s = "[" + ps.name + "]"
+	}
+	if ps.variadic {
+		s += "..."
+	}
+	return s
+}
+
+func isPositionalString(s string) bool {
+	return strings.HasPrefix(s, "<") || strings.HasPrefix(s, "[")
+}
+
+// newPositional parses "<name>", "[name]", "<name>..." or "[name]...".
+func newPositional(aPositionalString string) (*Positional, error) {
+	s := strings.TrimSuffix(aPositionalString, "...")
+	variadic := s != aPositionalString
+
+	var ps *Positional
+	switch {
+	case len(s) > 2 && s[0] == '<' && s[len(s)-1] == '>':
+		ps = NewPositional(s[1 : len(s)-1])
+	case len(s) > 2 && s[0] == '[' && s[len(s)-1] == ']':
+		ps = NewPositional(s[1 : len(s)-1]).Optional()
+	default:
+		return nil, &SchemaError{Rule: aPositionalString, Err: ErrWrongPositional}
+	}
+	if variadic {
+		ps.Variadic()
+	}
+	return ps, nil
+}
+
+// WithPositionals adds positional parameters in the order they are given.
+// Required parameters can't follow optional ones and only the last one can
+// be variadic.
+func (s *Schema) WithPositionals(positionals ...*Positional) (*Schema, error) {
+	for _, ps := range positionals {
+		if n := len(s.positionals); n > 0 {
+			last := s.positionals[n-1]
+			if last.variadic || (last.optional && !ps.optional) {
+				return nil, &SchemaError{Rule: ps.String(), Err: ErrWrongPositional}
+			}
+		}
+		for _, other := range s.positionals {
+			if other.name == ps.name {
+				return nil, &SchemaError{Rule: ps.String(), Err: ErrDuplicateFlag}
+			}
+		}
+		s.positionals = append(s.positionals, ps)
+	}
+	return s, nil
+}
+
+func (s *Schema) positionalOf(name string) (*Positional, error) {
+	for _, ps := range s.positionals {
+		if ps.name == name {
+			return ps, nil
+		}
+	}
+	return nil, &ArgumentError{name, ErrPositionalNotExist}
+}
+
+func (p *Parser) bindPositionals(arguments []string) error {
+	p.args = arguments
+	p.positionals = make(map[string][]string, 0)
+	for _, ps := range p.schema.positionals {
+		switch {
+		case len(arguments) == 0:
+			if !ps.optional {
+				return &ArgumentError{ps.name, ErrMissingArgument}
+			}
+		case ps.variadic:
+			p.positionals[ps.name] = arguments
+			arguments = nil
+		default:
+			p.positionals[ps.name] = arguments[:1]
+			arguments = arguments[1:]
+		}
+	}
+	if len(arguments) > 0 {
+		return &ArgumentError{arguments[0], ErrUnexpectedArgument}
+	}
+	return nil
+}
+
+// Args returns every positional argument in order.
+func (p *Parser) Args() []string {
+	return p.args
+}
+
+// PositionalOf returns the value of a positional parameter, or "" when an
+// optional one isn't given.
+func (p *Parser) PositionalOf(name string) (string, error) {
+	values, err := p.PositionalsOf(name)
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	return values[0], nil
+}
+
+// PositionalsOf returns every value of a variadic positional parameter.
+func (p *Parser) PositionalsOf(name string) ([]string, error) {
+	if _, err := p.schema.positionalOf(name); err != nil {
+		return nil, err
+	}
+	return p.positionals[name], nil
+}
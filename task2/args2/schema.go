@@ -0,0 +1,214 @@
+package args2
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// schemaField is a colon separated part of a schema entry, without its
+// quotes and escapes.
+type schemaField struct {
+	text   string
+	column int
+}
+
+type schemaEntry struct {
+	raw    string
+	column int
+	fields []schemaField
+}
+
+func (e schemaEntry) isDescription() bool {
+	return e.raw[0] == '"' || e.raw[0] == '\''
+}
+
+func isSchemaSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isSchemaEscape tells whether a backslash before c is removed, other
+// escapes such as "\," are kept for the list separators.
+func isSchemaEscape(c byte) bool {
+	return isSchemaSpace(c) || c == '"' || c == '\'' || c == ':' || c == '\\'
+}
+
+// lexSchema splits a schema string into entries and their fields.
+func lexSchema(aSchemaString string) ([]schemaEntry, error) {
+	column := func(i int) int {
+		return utf8.RuneCountInString(aSchemaString[:i]) + 1
+	}
+
+	var entries []schemaEntry
+	s := aSchemaString
+	for i := 0; i < len(s); {
+		if isSchemaSpace(s[i]) {
+			i++
+			continue
+		}
+
+		start, fieldStart := i, i
+		entry := schemaEntry{column: column(i)}
+		var sb strings.Builder
+		for i < len(s) && !isSchemaSpace(s[i]) {
+			switch c := s[i]; c {
+			case '\\':
+				if i+1 == len(s) {
+					return nil, &SchemaError{Rule: s[start:], Err: ErrTrailingEscape, Column: column(i)}
+				}
+				if !isSchemaEscape(s[i+1]) {
+					sb.WriteByte(c)
+				}
+				sb.WriteByte(s[i+1])
+				i += 2
+			case '"', '\'':
+				end := i + 1
+				for ; end < len(s) && s[end] != c; end++ {
+					if c == '"' && s[end] == '\\' && end+1 < len(s) && (s[end+1] == '"' || s[end+1] == '\\') {
+						end++
+					}
+					sb.WriteByte(s[end])
+				}
+				if end == len(s) {
+					return nil, &SchemaError{Rule: s[start:], Err: ErrUnterminatedQuote, Column: column(i)}
+				}
+				i = end + 1
+			case ':':
+				entry.fields = append(entry.fields, schemaField{sb.String(), column(fieldStart)})
+				sb.Reset()
+				i++
+				fieldStart = i
+			default:
+				sb.WriteByte(c)
+				i++
+			}
+		}
+		entry.fields = append(entry.fields, schemaField{sb.String(), column(fieldStart)})
+		entry.raw = s[start:i]
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// newSchema parses a schema string, a list of entries separated by any
+// whitespace:
+//
+//	p|port=PORT:int:80 "port to listen on"
+//	u:string:http://localhost:8080
+//	d:string:"/my logs"
+//	@Output <files>... "files to serve"
+//
+// A rule is "flag:type:default", the flag may list aliases separated by
+// "|" and end with "=PLACEHOLDER" and the default is everything after the
+// type, colons included. "<name>", "[name]" and a trailing "..." declare
+// positional parameters, "@Group" puts the rules after it in Group and a
+// quoted entry describes the rule or positional parameter before it.
+//
+// Single quotes and double quotes work as in SplitArguments. A backslash
+// escapes whitespace, quotes, "\\" and "\:", a colon that doesn't separate
+// fields, and is kept before any other character so list defaults such as
+// "d:[string]:a\,b" keep their escaped separators. Errors report the
+// column they are at.
+func newSchema(aSchemaString string) (*Schema, error) {
+	entries, err := lexSchema(aSchemaString)
+	if err != nil {
+		return nil, err
+	}
+
+	aSchema, _ := NewSchema()
+	group := ""
+	var describe func(string)
+	for _, e := range entries {
+		switch {
+		case e.isDescription():
+			if describe == nil || len(e.fields) > 1 {
+				return nil, &SchemaError{Rule: e.raw, Err: ErrWrongSchemaRule, Column: e.column}
+			}
+			describe(e.fields[0].text)
+			describe = nil
+			continue
+		case e.raw[0] == '@':
+			if len(e.raw) == 1 || len(e.fields) > 1 {
+				return nil, &SchemaError{Rule: e.raw, Err: ErrWrongSchemaRule, Column: e.column}
+			}
+			group = e.fields[0].text[1:]
+			describe = nil
+			continue
+		case isPositionalString(e.raw):
+			if len(e.fields) > 1 {
+				return nil, &SchemaError{Rule: e.raw, Err: ErrWrongPositional, Column: e.fields[1].column}
+			}
+			ps, err := newPositional(e.fields[0].text)
+			if err == nil {
+				_, err = aSchema.WithPositionals(ps)
+			}
+			if err != nil {
+				return nil, atColumn(err, e.column)
+			}
+			describe = func(description string) { ps.WithDescription(description) }
+		default:
+			sr, err := parseSchemaRule(e)
+			if err != nil {
+				return nil, err
+			}
+			if err := aSchema.addSchemaRule(sr.WithGroup(group)); err != nil {
+				return nil, atColumn(err, e.column)
+			}
+			describe = func(description string) { sr.WithDescription(description) }
+		}
+	}
+	return aSchema, nil
+}
+
+// newSchemaRule parses a schema string holding a single rule.
+func newSchemaRule(aSchemaRuleString string) (*SchemaRule, error) {
+	entries, err := lexSchema(aSchemaRuleString)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 || entries[0].isDescription() || isPositionalString(entries[0].raw) {
+		return nil, &SchemaError{Rule: aSchemaRuleString, Err: ErrWrongSchemaRule, Column: 1}
+	}
+	return parseSchemaRule(entries[0])
+}
+
+func parseSchemaRule(e schemaEntry) (*SchemaRule, error) {
+	if len(e.fields) < 2 {
+		return nil, &SchemaError{Rule: e.raw, Err: ErrWrongSchemaRule, Column: e.column + len(e.raw)}
+	}
+
+	flagField := e.fields[0]
+	placeholder := ""
+	if eq := strings.Index(flagField.text, "="); eq >= 0 {
+		flagField.text, placeholder = flagField.text[:eq], flagField.text[eq+1:]
+	}
+	names := strings.Split(flagField.text, "|")
+	for _, name := range names {
+		if name == "" {
+			return nil, &SchemaError{Rule: e.raw, Err: ErrWrongSchemaRule, Column: flagField.column}
+		}
+	}
+
+	typeField := e.fields[1]
+	if !isSupportArgType(typeField.text) {
+		return nil, &SchemaError{Rule: e.raw, Err: ErrNotSupportArgumentType, Column: typeField.column}
+	}
+
+	var defaults []string
+	for _, f := range e.fields[2:] {
+		defaults = append(defaults, f.text)
+	}
+
+	sr, err := NewSchemaRule(names[0], typeField.text, strings.Join(defaults, ":"))
+	if err != nil {
+		return nil, atColumn(err, e.column)
+	}
+	return sr.WithAliases(names[1:]...).WithPlaceholder(placeholder), nil
+}
+
+// atColumn sets the column of a schema error that doesn't have one.
+func atColumn(err error, column int) error {
+	if schemaErr, ok := err.(*SchemaError); ok && schemaErr.Column == 0 {
+		schemaErr.Column = column
+	}
+	return err
+}
@@ -0,0 +1,218 @@
+package args2
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+var ErrConfigSyntax = errors.New("config syntax error")
+var ErrUnknownConfigFormat = errors.New("unknown config format")
+
+// ConfigError reports a config file line that can't be read.
+type ConfigError struct {
+	Line int
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("config line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Source is where the value of a flag comes from.
+type Source int
+
+// Sources, in increasing precedence.
+const (
+	SourceDefault Source = iota
+	SourceFile
+	SourceEnv
+	SourceFlag
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	default:
+		return "default"
+	}
+}
+
+// WithEnv binds the flag to an environment variable.
+func (sr *SchemaRule) WithEnv(name string) *SchemaRule {
+	sr.env = name
+	return sr
+}
+
+// WithKey binds the flag to a config file key, keys in sections or nested
+// objects are joined with "." such as "server.port".
+func (sr *SchemaRule) WithKey(key string) *SchemaRule {
+	sr.key = key
+	return sr
+}
+
+// SetLookupEnv replaces os.LookupEnv as the way environment variables are
+// read.
+func (p *Parser) SetLookupEnv(lookupEnv func(string) (string, bool)) {
+	p.lookupEnv = lookupEnv
+}
+
+// resolve returns the value of a flag with precedence
+// flag > env > file > default.
+func (p *Parser) resolve(sr *SchemaRule) (string, Source) {
+	if v, ok := p.arguments[sr.getFlag()]; ok {
+		return v, SourceFlag
+	}
+	if sr.env != "" {
+		if v, ok := p.lookupEnv(sr.env); ok {
+			return v, SourceEnv
+		}
+	}
+	if sr.key != "" {
+		if v, ok := p.config[sr.key]; ok {
+			return v, SourceFile
+		}
+	}
+	return sr.getDefaultValue(), SourceDefault
+}
+
+// SourceOf tells where the value of flag comes from.
+func (p *Parser) SourceOf(flag string) (Source, error) {
+	sr, err := p.schema.schemaRuleOf(flag)
+	if err != nil {
+		return SourceDefault, err
+	}
+	_, source := p.resolve(sr)
+	return source, nil
+}
+
+// LoadConfig reads config values in format "json" or "ini", replacing the
+// ones loaded before. Load the config before parsing so the constraints
+// are checked against every source, loading it afterwards checks them
+// again.
+func (p *Parser) LoadConfig(r io.Reader, format string) error {
+	var config map[string]string
+	var err error
+	switch format {
+	case "json":
+		config, err = readJSONConfig(r)
+	case "ini":
+		config, err = readINIConfig(r)
+	default:
+		return ErrUnknownConfigFormat
+	}
+	if err != nil {
+		return err
+	}
+	p.config = config
+	if p.parsed {
+		return p.validate()
+	}
+	return nil
+}
+
+// LoadConfigFile reads a ".json" file or else an INI file.
+func (p *Parser) LoadConfigFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	format := "ini"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+	return p.LoadConfig(bytes.NewReader(data), format)
+}
+
+// readJSONConfig flattens a JSON object, arrays become lists such as
+// "1,2" with their commas escaped and objects are flattened into "parent.child" keys.
+func readJSONConfig(r io.Reader) (map[string]string, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]string)
+	var flatten func(prefix string, object map[string]interface{})
+	flatten = func(prefix string, object map[string]interface{}) {
+		for k, v := range object {
+			if nested, ok := v.(map[string]interface{}); ok {
+				flatten(prefix+k+".", nested)
+				continue
+			}
+			config[prefix+k] = jsonString(v)
+		}
+	}
+	flatten("", root)
+	return config, nil
+}
+
+func jsonString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		if len(v) == 0 {
+			return emptyList
+		}
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = escapeList(jsonString(e), ",")
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// readINIConfig reads "key = value" lines, keys after a "[section]" line
+// are "section.key". Lines starting with ";" or "#" are comments and
+// quotes around a value are removed.
+func readINIConfig(r io.Reader) (map[string]string, error) {
+	config := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || text[0] == ';' || text[0] == '#':
+			continue
+		case text[0] == '[':
+			if !strings.HasSuffix(text, "]") {
+				return nil, &ConfigError{line, ErrConfigSyntax}
+			}
+			section = strings.TrimSpace(text[1:len(text)-1]) + "."
+			continue
+		}
+
+		eq := strings.Index(text, "=")
+		if eq <= 0 {
+			return nil, &ConfigError{line, ErrConfigSyntax}
+		}
+		key := strings.TrimSpace(text[:eq])
+		value := strings.TrimSpace(text[eq+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		config[section+key] = value
+	}
+	return config, scanner.Err()
+}
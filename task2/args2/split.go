@@ -0,0 +1,65 @@
+package args2
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+var ErrTrailingEscape = errors.New("trailing backslash")
+
+// SplitArguments splits an arguments string like a shell does: arguments
+// are separated by whitespace, single quotes keep everything literally,
+// double quotes keep everything but \" and \\, and a backslash outside
+// quotes escapes the next character.
+func SplitArguments(aArgumentsString string) ([]string, error) {
+	var result []string
+	var current strings.Builder
+	inArgument := false
+	for i := 0; i < len(aArgumentsString); i++ {
+		c := aArgumentsString[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArgument {
+				result = append(result, current.String())
+				current.Reset()
+				inArgument = false
+			}
+		case c == '\\':
+			if i+1 == len(aArgumentsString) {
+				return nil, ErrTrailingEscape
+			}
+			i++
+			current.WriteByte(aArgumentsString[i])
+			inArgument = true
+		case c == '\'':
+			end := strings.IndexByte(aArgumentsString[i+1:], '\'')
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			current.WriteString(aArgumentsString[i+1 : i+1+end])
+			i += end + 1
+			inArgument = true
+		case c == '"':
+			i++
+			for ; i < len(aArgumentsString) && aArgumentsString[i] != '"'; i++ {
+				if aArgumentsString[i] == '\\' && i+1 < len(aArgumentsString) &&
+					(aArgumentsString[i+1] == '"' || aArgumentsString[i+1] == '\\') {
+					i++
+				}
+				current.WriteByte(aArgumentsString[i])
+			}
+			if i == len(aArgumentsString) {
+				return nil, ErrUnterminatedQuote
+			}
+			inArgument = true
+		default:
+			current.WriteByte(c)
+			inArgument = true
+		}
+	}
+	if inArgument {
+		result = append(result, current.String())
+	}
+	return result, nil
+}
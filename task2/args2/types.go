@@ -0,0 +1,164 @@
+package args2
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrDuplicateType = errors.New("type already supported")
+var ErrMapEntry = errors.New("map entry isn't key=value")
+
+// Value is a value of a type callers plug in with RegisterType. Set parses
+// a command line value, String formats the value back and Type is the type
+// code schemas use.
+type Value interface {
+	Set(string) error
+	String() string
+	Type() string
+}
+
+var registry = struct {
+	sync.RWMutex
+	types map[string]func() Value
+}{types: make(map[string]func() Value)}
+
+// RegisterType makes the type code newValue().Type() usable in schemas,
+// the value of a new Value is the type's default.
+func RegisterType(newValue func() Value) error {
+	typeCode := newValue().Type()
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.types[typeCode]; ok || isBuiltinType(typeCode) {
+		return &SchemaError{Rule: typeCode, Err: ErrDuplicateType}
+	}
+	registry.types[typeCode] = newValue
+	return nil
+}
+
+func registeredType(typeCode string) (func() Value, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	newValue, ok := registry.types[typeCode]
+	return newValue, ok
+}
+
+// ValueOf returns the value of a flag of a registered type.
+func (p *Parser) ValueOf(flag string) (Value, error) {
+	typeCode, err := p.schema.typeOf(flag)
+	if err != nil {
+		return nil, err
+	}
+	newValue, ok := registeredType(typeCode)
+	if !ok {
+		return nil, p.valueError(flag, typeCode, "", ErrNotSupportArgumentType)
+	}
+
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return nil, err
+	}
+	value := newValue()
+	if err := value.Set(v); err != nil {
+		return nil, p.valueError(flag, typeCode, v, err)
+	}
+	return value, nil
+}
+
+// FloatValueOf returns the value of a float flag.
+func (p *Parser) FloatValueOf(flag string) (float64, error) {
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, p.valueError(flag, "float", v, err)
+	}
+	return f, nil
+}
+
+// UintValueOf returns the value of a uint flag.
+func (p *Parser) UintValueOf(flag string) (uint, error) {
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return 0, err
+	}
+
+	u, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return 0, p.valueError(flag, "uint", v, err)
+	}
+	return uint(u), nil
+}
+
+// DurationValueOf returns the value of a duration flag such as "1h30m".
+func (p *Parser) DurationValueOf(flag string) (time.Duration, error) {
+	v, err := p.StringValueOf(flag)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, p.valueError(flag, "duration", v, err)
+	}
+	return d, nil
+}
+
+// TimeValueOf returns the value of an RFC 3339 time flag, the zero time
+// when it is empty.
+func (p *Parser) TimeValueOf(flag string) (time.Time, error) {
+	v, err := p.StringValueOf(flag)
+	if err != nil || v == "" {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, p.valueError(flag, "time", v, err)
+	}
+	return t, nil
+}
+
+// MapValueOf returns the value of a map flag such as "k=v,k2=v2".
+func (p *Parser) MapValueOf(flag string) (map[string]string, error) {
+	entries, v, err := p.listOf(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	if v == "" {
+		return result, nil
+	}
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, p.valueError(flag, "map", v, ErrMapEntry)
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result, nil
+}
+
+// FloatListOf returns the value of a [float] flag.
+func (p *Parser) FloatListOf(flag string) (result []float64, err error) {
+	elements, v, err := p.listOf(flag)
+	if err != nil {
+		return
+	}
+
+	for _, n := range elements {
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return []float64{}, p.valueError(flag, "[float]", v, err)
+		}
+		result = append(result, f)
+	}
+	return
+}